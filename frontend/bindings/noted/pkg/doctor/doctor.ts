@@ -0,0 +1,46 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+/**
+ * Doctor binds Diagnose and Fix for the editor windows.
+ * @module
+ */
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * Check reports the problems of the notespace at root.
+ */
+export function Check(root: string): $CancellablePromise<$models.Report> {
+    return $Call.ByID(1327696171, root).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * CheckLinks reports the broken links, images and anchors of the notespace at root.
+ */
+export function CheckLinks(root: string): $CancellablePromise<$models.Report> {
+    return $Call.ByID(2926930356, root).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * Fix applies the automatic fixes of the issues in ids, or of all fixable
+ * issues if ids is empty. Read-only windows may not fix anything.
+ */
+export function Fix(root: string, ids: string[]): $CancellablePromise<$models.Report> {
+    return $Call.ByID(1527674044, root, ids).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = $models.Report.createFrom;
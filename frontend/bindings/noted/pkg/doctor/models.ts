@@ -0,0 +1,132 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+export enum Check {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    CheckConfig = "config",
+    CheckGit = "git",
+    CheckBrokenLink = "broken-link",
+    CheckBrokenImage = "broken-image",
+    CheckBrokenAnchor = "broken-anchor",
+    CheckUnreadable = "unreadable",
+    CheckSymlink = "symlink",
+    CheckConflict = "conflict",
+    CheckOversized = "oversized",
+    CheckCaseCollision = "case-collision",
+};
+
+/**
+ * Issue is one problem found in a notespace. IDs are stable between runs as
+ * long as the problem stays where it is, so a report can be fixed selectively.
+ */
+export class Issue {
+    "id": string;
+    "check": Check;
+    "severity": Severity;
+    "path": string;
+    "line"?: number;
+    "message": string;
+
+    /**
+     * Target is the link or symlink target the issue is about.
+     */
+    "target"?: string;
+
+    /**
+     * Fix describes the automatic fix; empty if the issue must be fixed by hand.
+     */
+    "fix"?: string;
+
+    /** Creates a new Issue instance. */
+    constructor($$source: Partial<Issue> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("check" in $$source)) {
+            this["check"] = Check.$zero;
+        }
+        if (!("severity" in $$source)) {
+            this["severity"] = Severity.$zero;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("message" in $$source)) {
+            this["message"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Issue instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Issue {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Issue($$parsedSource as Partial<Issue>);
+    }
+}
+
+export class Report {
+    "root": string;
+    "files": number;
+    "issues": Issue[];
+
+    /**
+     * Fixed lists the issues fixed before the report was made.
+     */
+    "fixed"?: Issue[];
+
+    /** Creates a new Report instance. */
+    constructor($$source: Partial<Report> = {}) {
+        if (!("root" in $$source)) {
+            this["root"] = "";
+        }
+        if (!("files" in $$source)) {
+            this["files"] = 0;
+        }
+        if (!("issues" in $$source)) {
+            this["issues"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Report instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Report {
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("issues" in $$parsedSource) {
+            $$parsedSource["issues"] = $$createField2_0($$parsedSource["issues"]);
+        }
+        if ("fixed" in $$parsedSource) {
+            $$parsedSource["fixed"] = $$createField3_0($$parsedSource["fixed"]);
+        }
+        return new Report($$parsedSource as Partial<Report>);
+    }
+}
+
+export enum Severity {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    SeverityError = "error",
+    SeverityWarning = "warning",
+};
+
+// Private type creation functions
+const $$createType0 = Issue.createFrom;
+const $$createType1 = $Create.Array($$createType0);
@@ -5,41 +5,272 @@
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as links$0 from "../links/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as query$0 from "../query/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as search$0 from "../search/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as tags$0 from "../tags/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as workspace$0 from "../workspace/models.js";
+
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
-export function CreateNewRepo(): $CancellablePromise<string> {
-    return $Call.ByID(4245064457);
+export function CreateNewRepo(): $CancellablePromise<$models.Inspection> {
+    return $Call.ByID(4245064457).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * FindFiles fuzzy-matches query against the paths and note titles of the calling
+ * window's notespace. Recently opened notes rank higher; an empty query lists
+ * them first.
+ */
+export function FindFiles(query: string, limit: number): $CancellablePromise<search$0.FileMatch[]> {
+    return $Call.ByID(3271504901, query, limit).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+/**
+ * FindTagged returns the notes of the calling window's notespace matching a tag
+ * query such as "#project AND (draft OR review) NOT archived".
+ */
+export function FindTagged(query: string): $CancellablePromise<tags$0.Note[]> {
+    return $Call.ByID(1481580502, query).then(($result: any) => {
+        return $$createType4($result);
+    });
 }
 
+/**
+ * GetBacklinks returns the links to path from the notes of the calling window's
+ * notespace, including [[wiki-links]] and embeds. The first call waits for the
+ * links to be parsed.
+ */
+export function GetBacklinks(path: string): $CancellablePromise<links$0.Link[]> {
+    return $Call.ByID(3809416993, path).then(($result: any) => {
+        return $$createType6($result);
+    });
+}
+
+/**
+ * GetCurrentNotespace returns the notespace shown in the calling window.
+ */
 export function GetCurrentNotespace(): $CancellablePromise<$models.Notespace> {
     return $Call.ByID(2567671154).then(($result: any) => {
-        return $$createType0($result);
+        return $$createType7($result);
     });
 }
 
 export function GetEditorState(): $CancellablePromise<$models.EditorState> {
     return $Call.ByID(1387844055).then(($result: any) => {
-        return $$createType1($result);
+        return $$createType8($result);
+    });
+}
+
+/**
+ * GetGraph returns the notes of the calling window's notespace as nodes, with
+ * their titles, tags and folders, and the links, embeds and shared tags between
+ * them as edges. Note contents are not sent.
+ */
+export function GetGraph(opts: links$0.GraphOptions): $CancellablePromise<links$0.Graph> {
+    return $Call.ByID(917671437, opts).then(($result: any) => {
+        return $$createType9($result);
     });
 }
 
 export function GetNotespaceFromPaths(paths: string[]): $CancellablePromise<$models.Notespace[]> {
     return $Call.ByID(1883388617, paths).then(($result: any) => {
-        return $$createType2($result);
+        return $$createType10($result);
+    });
+}
+
+/**
+ * GetOpenTabs returns the notes open in the calling window.
+ */
+export function GetOpenTabs(): $CancellablePromise<$models.Tabs> {
+    return $Call.ByID(2628438187).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+/**
+ * GetOutgoingLinks returns the links of the note at path to files of the calling
+ * window's notespace. Links to missing files have an empty path.
+ */
+export function GetOutgoingLinks(path: string): $CancellablePromise<links$0.Link[]> {
+    return $Call.ByID(1710520050, path).then(($result: any) => {
+        return $$createType6($result);
+    });
+}
+
+/**
+ * GetRecentNotespaces returns the recents kept by the settings store, pinned first.
+ */
+export function GetRecentNotespaces(): $CancellablePromise<$models.Notespace[]> {
+    return $Call.ByID(458557943).then(($result: any) => {
+        return $$createType10($result);
+    });
+}
+
+/**
+ * GetTags lists the tags of the calling window's notespace, from frontmatter
+ * and inline #hashtags, nested by their slashes and with the number of notes
+ * carrying each.
+ */
+export function GetTags(): $CancellablePromise<tags$0.Tag[]> {
+    return $Call.ByID(859243410).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+/**
+ * GetWorkspaceState returns the remembered tabs, bookmarks and cursors of the
+ * calling window's notespace.
+ */
+export function GetWorkspaceState(): $CancellablePromise<workspace$0.State> {
+    return $Call.ByID(2462526649).then(($result: any) => {
+        return $$createType14($result);
+    });
+}
+
+/**
+ * InitialiseNotespace creates whatever dir is missing (git repository, config file)
+ * and opens it in an editor window.
+ */
+export function InitialiseNotespace(dir: string): $CancellablePromise<$models.Inspection> {
+    return $Call.ByID(2278879908, dir).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * InspectDirectory reports the notespace state of dir without changing anything.
+ */
+export function InspectDirectory(dir: string): $CancellablePromise<$models.Inspection> {
+    return $Call.ByID(3123600182, dir).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function OpenExisitingRepo(): $CancellablePromise<$models.Inspection> {
+    return $Call.ByID(1580518959).then(($result: any) => {
+        return $$createType0($result);
     });
 }
 
-export function OpenExisitingRepo(): $CancellablePromise<string> {
-    return $Call.ByID(1580518959);
+export function OpenRepoDirectory(dir: string): $CancellablePromise<$models.Inspection> {
+    return $Call.ByID(2311084740, dir).then(($result: any) => {
+        return $$createType0($result);
+    });
 }
 
-export function OpenRepoDirectory(dir: string): $CancellablePromise<string> {
-    return $Call.ByID(2311084740, dir);
+/**
+ * RemoveRegistryEntry deletes a registry entry of the current notespace.
+ */
+export function RemoveRegistryEntry(key: string): $CancellablePromise<$models.Config> {
+    return $Call.ByID(3977113638, key).then(($result: any) => {
+        return $$createType15($result);
+    });
+}
+
+/**
+ * RenameTag replaces the tag from, and the tags nested below it, with to in
+ * every note of the calling window's notespace. When a note cannot be written,
+ * the notes rewritten so far are put back.
+ */
+export function RenameTag($from: string, to: string): $CancellablePromise<$models.TagRename> {
+    return $Call.ByID(4055350265, $from, to).then(($result: any) => {
+        return $$createType16($result);
+    });
+}
+
+/**
+ * RunQuery evaluates a TABLE or LIST query over the frontmatter, tags and file
+ * details of the notes of the calling window's notespace. See query.Query for
+ * the language.
+ */
+export function RunQuery(source: string): $CancellablePromise<query$0.Result> {
+    return $Call.ByID(578761166, source).then(($result: any) => {
+        return $$createType17($result);
+    });
+}
+
+/**
+ * SaveOpenTabs remembers the notes open in the calling window for the next
+ * launch, in the notespace's workspace state.
+ */
+export function SaveOpenTabs(tabs: $models.Tabs): $CancellablePromise<void> {
+    return $Call.ByID(2004302284, tabs);
+}
+
+/**
+ * SearchNotespace runs a full-text query over the notes of the calling window's
+ * notespace. Words are matched by their stem; "quoted phrases", prefix* and
+ * -excluded words are supported. The first search waits for the index to be built.
+ */
+export function SearchNotespace(query: string, limit: number): $CancellablePromise<search$0.Result[]> {
+    return $Call.ByID(3191625785, query, limit).then(($result: any) => {
+        return $$createType19($result);
+    });
+}
+
+/**
+ * SetRegistryEntry adds or replaces a registry entry of the current notespace.
+ */
+export function SetRegistryEntry(key: string, value: string): $CancellablePromise<$models.Config> {
+    return $Call.ByID(2544186400, key, value).then(($result: any) => {
+        return $$createType15($result);
+    });
+}
+
+/**
+ * UpdateConfig applies patch to the current notespace's config.
+ */
+export function UpdateConfig(patch: $models.ConfigPatch): $CancellablePromise<$models.Config> {
+    return $Call.ByID(689592314, patch).then(($result: any) => {
+        return $$createType15($result);
+    });
+}
+
+/**
+ * UpdateWorkspaceState applies patch to the calling window's notespace state.
+ * The file is written shortly after the last update.
+ */
+export function UpdateWorkspaceState(patch: workspace$0.Patch): $CancellablePromise<workspace$0.State> {
+    return $Call.ByID(4145983178, patch).then(($result: any) => {
+        return $$createType14($result);
+    });
 }
 
 // Private type creation functions
-const $$createType0 = $models.Notespace.createFrom;
-const $$createType1 = $models.EditorState.createFrom;
-const $$createType2 = $Create.Array($$createType0);
+const $$createType0 = $models.Inspection.createFrom;
+const $$createType1 = search$0.FileMatch.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = tags$0.Note.createFrom;
+const $$createType4 = $Create.Array($$createType3);
+const $$createType5 = links$0.Link.createFrom;
+const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = $models.Notespace.createFrom;
+const $$createType8 = $models.EditorState.createFrom;
+const $$createType9 = links$0.Graph.createFrom;
+const $$createType10 = $Create.Array($$createType7);
+const $$createType11 = $models.Tabs.createFrom;
+const $$createType12 = tags$0.Tag.createFrom;
+const $$createType13 = $Create.Array($$createType12);
+const $$createType14 = workspace$0.State.createFrom;
+const $$createType15 = $models.Config.createFrom;
+const $$createType16 = $models.TagRename.createFrom;
+const $$createType17 = query$0.Result.createFrom;
+const $$createType18 = search$0.Result.createFrom;
+const $$createType19 = $Create.Array($$createType18);
@@ -8,6 +8,11 @@ export {
 
 export {
     Config,
+    ConfigPatch,
     EditorState,
-    Notespace
+    FieldError,
+    Inspection,
+    Notespace,
+    Tabs,
+    TagRename
 } from "./models.js";
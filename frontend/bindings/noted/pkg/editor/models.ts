@@ -6,6 +6,7 @@
 import { Create as $Create } from "@wailsio/runtime";
 
 export class Config {
+    "version": number;
     "name": string;
     "description"?: string;
     "repository"?: string;
@@ -15,6 +16,9 @@ export class Config {
 
     /** Creates a new Config instance. */
     constructor($$source: Partial<Config> = {}) {
+        if (!("version" in $$source)) {
+            this["version"] = 0;
+        }
         if (!("name" in $$source)) {
             this["name"] = "";
         }
@@ -29,19 +33,53 @@ export class Config {
      * Creates a new Config instance from a string or object.
      */
     static createFrom($$source: any = {}): Config {
-        const $$createField4_0 = $$createType0;
+        const $$createField5_0 = $$createType0;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("registry" in $$parsedSource) {
-            $$parsedSource["registry"] = $$createField4_0($$parsedSource["registry"]);
+            $$parsedSource["registry"] = $$createField5_0($$parsedSource["registry"]);
         }
         return new Config($$parsedSource as Partial<Config>);
     }
 }
 
+/**
+ * ConfigPatch holds the fields to change. Nil fields are left as they are;
+ * an empty string clears an optional field.
+ */
+export class ConfigPatch {
+    "name"?: string | null;
+    "description"?: string | null;
+    "repository"?: string | null;
+    "homepage"?: string | null;
+    "author"?: string | null;
+
+    /** Creates a new ConfigPatch instance. */
+    constructor($$source: Partial<ConfigPatch> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ConfigPatch instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ConfigPatch {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ConfigPatch($$parsedSource as Partial<ConfigPatch>);
+    }
+}
+
 export class EditorState {
+    "root": string;
+    "readOnly": boolean;
 
     /** Creates a new EditorState instance. */
     constructor($$source: Partial<EditorState> = {}) {
+        if (!("root" in $$source)) {
+            this["root"] = "";
+        }
+        if (!("readOnly" in $$source)) {
+            this["readOnly"] = false;
+        }
 
         Object.assign(this, $$source);
     }
@@ -55,9 +93,107 @@ export class EditorState {
     }
 }
 
+/**
+ * FieldError points at a single problem in the config file.
+ */
+export class FieldError {
+    "field": string;
+    "message": string;
+    "line"?: number;
+    "column"?: number;
+
+    /** Creates a new FieldError instance. */
+    constructor($$source: Partial<FieldError> = {}) {
+        if (!("field" in $$source)) {
+            this["field"] = "";
+        }
+        if (!("message" in $$source)) {
+            this["message"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FieldError instance from a string or object.
+     */
+    static createFrom($$source: any = {}): FieldError {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FieldError($$parsedSource as Partial<FieldError>);
+    }
+}
+
+/**
+ * Inspection describes what a folder is missing before it can be used as a notespace.
+ */
+export class Inspection {
+    "path": string;
+    "isGitRepo": boolean;
+    "hasConfig": boolean;
+    "configValid": boolean;
+    "configError"?: string;
+
+    /**
+     * ConfigErrors locates each problem in the config file.
+     */
+    "configErrors"?: FieldError[];
+    "config": Config | null;
+
+    /**
+     * Parent is the root of an enclosing notespace when Path is nested inside one.
+     */
+    "parent"?: string;
+
+    /**
+     * Opened is true when an editor window was opened for Path.
+     */
+    "opened": boolean;
+
+    /** Creates a new Inspection instance. */
+    constructor($$source: Partial<Inspection> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("isGitRepo" in $$source)) {
+            this["isGitRepo"] = false;
+        }
+        if (!("hasConfig" in $$source)) {
+            this["hasConfig"] = false;
+        }
+        if (!("configValid" in $$source)) {
+            this["configValid"] = false;
+        }
+        if (!("config" in $$source)) {
+            this["config"] = null;
+        }
+        if (!("opened" in $$source)) {
+            this["opened"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Inspection instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Inspection {
+        const $$createField5_0 = $$createType2;
+        const $$createField6_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("configErrors" in $$parsedSource) {
+            $$parsedSource["configErrors"] = $$createField5_0($$parsedSource["configErrors"]);
+        }
+        if ("config" in $$parsedSource) {
+            $$parsedSource["config"] = $$createField6_0($$parsedSource["config"]);
+        }
+        return new Inspection($$parsedSource as Partial<Inspection>);
+    }
+}
+
 export class Notespace {
     "config": Config | null;
     "path": string;
+    "pinned": boolean;
 
     /** Creates a new Notespace instance. */
     constructor($$source: Partial<Notespace> = {}) {
@@ -67,6 +203,9 @@ export class Notespace {
         if (!("path" in $$source)) {
             this["path"] = "";
         }
+        if (!("pinned" in $$source)) {
+            this["pinned"] = false;
+        }
 
         Object.assign(this, $$source);
     }
@@ -75,7 +214,7 @@ export class Notespace {
      * Creates a new Notespace instance from a string or object.
      */
     static createFrom($$source: any = {}): Notespace {
-        const $$createField0_0 = $$createType2;
+        const $$createField0_0 = $$createType4;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("config" in $$parsedSource) {
             $$parsedSource["config"] = $$createField0_0($$parsedSource["config"]);
@@ -84,7 +223,81 @@ export class Notespace {
     }
 }
 
+/**
+ * Tabs are the notes open in an editor window.
+ */
+export class Tabs {
+    "paths": string[];
+    "active": string;
+
+    /** Creates a new Tabs instance. */
+    constructor($$source: Partial<Tabs> = {}) {
+        if (!("paths" in $$source)) {
+            this["paths"] = [];
+        }
+        if (!("active" in $$source)) {
+            this["active"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Tabs instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Tabs {
+        const $$createField0_0 = $$createType5;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("paths" in $$parsedSource) {
+            $$parsedSource["paths"] = $$createField0_0($$parsedSource["paths"]);
+        }
+        return new Tabs($$parsedSource as Partial<Tabs>);
+    }
+}
+
+/**
+ * TagRename is the outcome of renaming a tag.
+ */
+export class TagRename {
+    /**
+     * Files are the notes that were rewritten, sorted.
+     */
+    "files": string[];
+
+    /**
+     * Count is the number of tags replaced across them.
+     */
+    "count": number;
+
+    /** Creates a new TagRename instance. */
+    constructor($$source: Partial<TagRename> = {}) {
+        if (!("files" in $$source)) {
+            this["files"] = [];
+        }
+        if (!("count" in $$source)) {
+            this["count"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TagRename instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TagRename {
+        const $$createField0_0 = $$createType5;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField0_0($$parsedSource["files"]);
+        }
+        return new TagRename($$parsedSource as Partial<TagRename>);
+    }
+}
+
 // Private type creation functions
 const $$createType0 = $Create.Map($Create.Any, $Create.Any);
-const $$createType1 = Config.createFrom;
-const $$createType2 = $Create.Nullable($$createType1);
+const $$createType1 = FieldError.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = Config.createFrom;
+const $$createType4 = $Create.Nullable($$createType3);
+const $$createType5 = $Create.Array($Create.Any);
@@ -7,5 +7,16 @@ export {
 };
 
 export {
-    Node
+    DirOrder,
+    GrepOptions,
+    GrepRange,
+    GrepSummary,
+    Node,
+    ReplaceFile,
+    ReplaceLine,
+    ReplaceOptions,
+    ReplacePreview,
+    ReplaceRecord,
+    ReplaceSelection,
+    SortMode
 } from "./models.js";
@@ -9,6 +9,186 @@ import { Create as $Create } from "@wailsio/runtime";
 // @ts-ignore: Unused imports
 import * as time$0 from "../../../time/models.js";
 
+/**
+ * DirOrder is the content of an ORDER_FILE.
+ */
+export class DirOrder {
+    /**
+     * Sort overrides the scanner's mode for this folder only. Empty keeps the scanner's mode.
+     */
+    "sort"?: SortMode;
+
+    /**
+     * Order lists entry names that go first, in the given order. Unlisted entries follow,
+     * sorted by the folder's mode.
+     */
+    "order"?: string[];
+
+    /** Creates a new DirOrder instance. */
+    constructor($$source: Partial<DirOrder> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DirOrder instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DirOrder {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("order" in $$parsedSource) {
+            $$parsedSource["order"] = $$createField1_0($$parsedSource["order"]);
+        }
+        return new DirOrder($$parsedSource as Partial<DirOrder>);
+    }
+}
+
+export class GrepOptions {
+    /**
+     * ID is echoed in every event so a window can ignore batches of older searches.
+     */
+    "id": string;
+    "pattern": string;
+    "regex": boolean;
+    "caseSensitive": boolean;
+    "wholeWord": boolean;
+
+    /**
+     * Include and Exclude are globs. Globs without a slash match file or folder
+     * names at any depth; others match the path relative to the root, where **
+     * spans folders. An empty Include searches every file.
+     */
+    "include": string[];
+    "exclude": string[];
+
+    /**
+     * Context is the number of lines sent before and after each match.
+     */
+    "context": number;
+    "maxMatches": number;
+
+    /** Creates a new GrepOptions instance. */
+    constructor($$source: Partial<GrepOptions> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("pattern" in $$source)) {
+            this["pattern"] = "";
+        }
+        if (!("regex" in $$source)) {
+            this["regex"] = false;
+        }
+        if (!("caseSensitive" in $$source)) {
+            this["caseSensitive"] = false;
+        }
+        if (!("wholeWord" in $$source)) {
+            this["wholeWord"] = false;
+        }
+        if (!("include" in $$source)) {
+            this["include"] = [];
+        }
+        if (!("exclude" in $$source)) {
+            this["exclude"] = [];
+        }
+        if (!("context" in $$source)) {
+            this["context"] = 0;
+        }
+        if (!("maxMatches" in $$source)) {
+            this["maxMatches"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GrepOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GrepOptions {
+        const $$createField5_0 = $$createType0;
+        const $$createField6_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("include" in $$parsedSource) {
+            $$parsedSource["include"] = $$createField5_0($$parsedSource["include"]);
+        }
+        if ("exclude" in $$parsedSource) {
+            $$parsedSource["exclude"] = $$createField6_0($$parsedSource["exclude"]);
+        }
+        return new GrepOptions($$parsedSource as Partial<GrepOptions>);
+    }
+}
+
+/**
+ * GrepRange is a match within a line, in characters from the start of the line.
+ */
+export class GrepRange {
+    "start": number;
+    "end": number;
+
+    /** Creates a new GrepRange instance. */
+    constructor($$source: Partial<GrepRange> = {}) {
+        if (!("start" in $$source)) {
+            this["start"] = 0;
+        }
+        if (!("end" in $$source)) {
+            this["end"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GrepRange instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GrepRange {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new GrepRange($$parsedSource as Partial<GrepRange>);
+    }
+}
+
+/**
+ * GrepSummary is returned once a search has finished.
+ */
+export class GrepSummary {
+    "id": string;
+    "files": number;
+    "matchedFiles": number;
+    "matches": number;
+
+    /**
+     * Truncated is set when the search stopped at MaxMatches.
+     */
+    "truncated": boolean;
+
+    /** Creates a new GrepSummary instance. */
+    constructor($$source: Partial<GrepSummary> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("files" in $$source)) {
+            this["files"] = 0;
+        }
+        if (!("matchedFiles" in $$source)) {
+            this["matchedFiles"] = 0;
+        }
+        if (!("matches" in $$source)) {
+            this["matches"] = 0;
+        }
+        if (!("truncated" in $$source)) {
+            this["truncated"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GrepSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GrepSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new GrepSummary($$parsedSource as Partial<GrepSummary>);
+    }
+}
+
 export class Node {
     "name": string;
     "path": string;
@@ -19,6 +199,7 @@ export class Node {
     "type": string;
     "size": number;
     "modified": time$0.Time;
+    "created": time$0.Time;
     "children"?: Node[];
     "isHidden": boolean;
     "extension"?: string;
@@ -40,6 +221,9 @@ export class Node {
         if (!("modified" in $$source)) {
             this["modified"] = null;
         }
+        if (!("created" in $$source)) {
+            this["created"] = null;
+        }
         if (!("isHidden" in $$source)) {
             this["isHidden"] = false;
         }
@@ -51,15 +235,297 @@ export class Node {
      * Creates a new Node instance from a string or object.
      */
     static createFrom($$source: any = {}): Node {
-        const $$createField5_0 = $$createType1;
+        const $$createField6_0 = $$createType2;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("children" in $$parsedSource) {
-            $$parsedSource["children"] = $$createField5_0($$parsedSource["children"]);
+            $$parsedSource["children"] = $$createField6_0($$parsedSource["children"]);
         }
         return new Node($$parsedSource as Partial<Node>);
     }
 }
 
+/**
+ * ReplaceFile is the preview of one file. Hash identifies the content the
+ * preview was made from; applying fails if the file changed since.
+ */
+export class ReplaceFile {
+    "path": string;
+    "hash": string;
+    "lines": ReplaceLine[];
+
+    /** Creates a new ReplaceFile instance. */
+    constructor($$source: Partial<ReplaceFile> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("hash" in $$source)) {
+            this["hash"] = "";
+        }
+        if (!("lines" in $$source)) {
+            this["lines"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReplaceFile instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReplaceFile {
+        const $$createField2_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("lines" in $$parsedSource) {
+            $$parsedSource["lines"] = $$createField2_0($$parsedSource["lines"]);
+        }
+        return new ReplaceFile($$parsedSource as Partial<ReplaceFile>);
+    }
+}
+
+/**
+ * ReplaceLine is a line that a replacement changes. Ranges are the matches in
+ * Before, in characters.
+ */
+export class ReplaceLine {
+    "line": number;
+    "before": string;
+    "after": string;
+    "ranges": GrepRange[];
+
+    /** Creates a new ReplaceLine instance. */
+    constructor($$source: Partial<ReplaceLine> = {}) {
+        if (!("line" in $$source)) {
+            this["line"] = 0;
+        }
+        if (!("before" in $$source)) {
+            this["before"] = "";
+        }
+        if (!("after" in $$source)) {
+            this["after"] = "";
+        }
+        if (!("ranges" in $$source)) {
+            this["ranges"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReplaceLine instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReplaceLine {
+        const $$createField3_0 = $$createType6;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("ranges" in $$parsedSource) {
+            $$parsedSource["ranges"] = $$createField3_0($$parsedSource["ranges"]);
+        }
+        return new ReplaceLine($$parsedSource as Partial<ReplaceLine>);
+    }
+}
+
+export class ReplaceOptions {
+    "search": GrepOptions;
+
+    /**
+     * Replacement may refer to groups as $1 or ${name} when Search.Regex is set;
+     * otherwise it is inserted as written.
+     */
+    "replacement": string;
+
+    /** Creates a new ReplaceOptions instance. */
+    constructor($$source: Partial<ReplaceOptions> = {}) {
+        if (!("search" in $$source)) {
+            this["search"] = (new GrepOptions());
+        }
+        if (!("replacement" in $$source)) {
+            this["replacement"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReplaceOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReplaceOptions {
+        const $$createField0_0 = $$createType7;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("search" in $$parsedSource) {
+            $$parsedSource["search"] = $$createField0_0($$parsedSource["search"]);
+        }
+        return new ReplaceOptions($$parsedSource as Partial<ReplaceOptions>);
+    }
+}
+
+export class ReplacePreview {
+    "files": ReplaceFile[];
+    "matches": number;
+
+    /**
+     * Truncated is set when the preview stopped at Search.MaxMatches. Only
+     * previewed files can be replaced.
+     */
+    "truncated": boolean;
+
+    /** Creates a new ReplacePreview instance. */
+    constructor($$source: Partial<ReplacePreview> = {}) {
+        if (!("files" in $$source)) {
+            this["files"] = [];
+        }
+        if (!("matches" in $$source)) {
+            this["matches"] = 0;
+        }
+        if (!("truncated" in $$source)) {
+            this["truncated"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReplacePreview instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReplacePreview {
+        const $$createField0_0 = $$createType9;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField0_0($$parsedSource["files"]);
+        }
+        return new ReplacePreview($$parsedSource as Partial<ReplacePreview>);
+    }
+}
+
+/**
+ * ReplaceRecord describes an applied replacement. Pass ID to UndoReplace to
+ * revert it.
+ */
+export class ReplaceRecord {
+    "id": string;
+    "time": time$0.Time;
+    "pattern": string;
+    "replacement": string;
+    "files": string[];
+    "lines": number;
+
+    /** Creates a new ReplaceRecord instance. */
+    constructor($$source: Partial<ReplaceRecord> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("time" in $$source)) {
+            this["time"] = null;
+        }
+        if (!("pattern" in $$source)) {
+            this["pattern"] = "";
+        }
+        if (!("replacement" in $$source)) {
+            this["replacement"] = "";
+        }
+        if (!("files" in $$source)) {
+            this["files"] = [];
+        }
+        if (!("lines" in $$source)) {
+            this["lines"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReplaceRecord instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReplaceRecord {
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField4_0($$parsedSource["files"]);
+        }
+        return new ReplaceRecord($$parsedSource as Partial<ReplaceRecord>);
+    }
+}
+
+/**
+ * ReplaceSelection picks the lines of a previewed file to change. No lines
+ * selects all of them.
+ */
+export class ReplaceSelection {
+    "path": string;
+    "hash": string;
+    "lines": number[];
+
+    /** Creates a new ReplaceSelection instance. */
+    constructor($$source: Partial<ReplaceSelection> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("hash" in $$source)) {
+            this["hash"] = "";
+        }
+        if (!("lines" in $$source)) {
+            this["lines"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReplaceSelection instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReplaceSelection {
+        const $$createField2_0 = $$createType10;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("lines" in $$parsedSource) {
+            $$parsedSource["lines"] = $$createField2_0($$parsedSource["lines"]);
+        }
+        return new ReplaceSelection($$parsedSource as Partial<ReplaceSelection>);
+    }
+}
+
+export enum SortMode {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * "2-bar" before "10-foo", case-insensitive
+     */
+    SortNatural = "natural",
+
+    /**
+     * directories first, then natural
+     */
+    SortDirsFirst = "dirs-first",
+
+    /**
+     * most recently modified first
+     */
+    SortModified = "modified",
+
+    /**
+     * most recently created first
+     */
+    SortCreated = "created",
+
+    /**
+     * frontmatter title, falling back to the name
+     */
+    SortTitle = "title",
+
+    /**
+     * only valid in an order file; entries listed there first
+     */
+    SortManual = "manual",
+};
+
 // Private type creation functions
-const $$createType0 = Node.createFrom;
-const $$createType1 = $Create.Array($$createType0);
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = Node.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = ReplaceLine.createFrom;
+const $$createType4 = $Create.Array($$createType3);
+const $$createType5 = GrepRange.createFrom;
+const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = GrepOptions.createFrom;
+const $$createType8 = ReplaceFile.createFrom;
+const $$createType9 = $Create.Array($$createType8);
+const $$createType10 = $Create.Array($Create.Any);
@@ -5,10 +5,25 @@
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as frontmatter$0 from "../frontmatter/models.js";
+
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
+/**
+ * ApplyReplace changes the selected lines of previewed files. Either every file
+ * is written or, if one fails, none is. A journal is kept so that the whole
+ * replacement can be reverted with UndoReplace.
+ */
+export function ApplyReplace(root: string, opts: $models.ReplaceOptions, selection: $models.ReplaceSelection[]): $CancellablePromise<$models.ReplaceRecord> {
+    return $Call.ByID(1205594821, root, opts, selection).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
 export function CreateNewDir(path: string): $CancellablePromise<void> {
     return $Call.ByID(3508882504, path);
 }
@@ -17,19 +32,123 @@ export function CreateNewFile(path: string, content: string): $CancellablePromis
     return $Call.ByID(3344246879, path, content);
 }
 
+/**
+ * EditFrontmatter sets and removes top-level keys in content and returns the
+ * new content. The rest of the frontmatter and the body are left untouched.
+ */
+export function EditFrontmatter(content: string, edits: frontmatter$0.Edit[]): $CancellablePromise<string> {
+    return $Call.ByID(1214512209, content, edits);
+}
+
+/**
+ * GetDirOrder returns the override stored in dir, or an empty order if there is none.
+ */
+export function GetDirOrder(dir: string): $CancellablePromise<$models.DirOrder> {
+    return $Call.ByID(905511966, dir).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
 export function GetFileData(path: string): $CancellablePromise<string> {
     return $Call.ByID(152262409, path);
 }
 
 export function GetFileTree(root: string): $CancellablePromise<$models.Node> {
     return $Call.ByID(2870357009, root).then(($result: any) => {
-        return $$createType0($result);
+        return $$createType2($result);
+    });
+}
+
+/**
+ * GetFrontmatter reads the frontmatter of the note at path.
+ */
+export function GetFrontmatter(path: string): $CancellablePromise<frontmatter$0.Frontmatter> {
+    return $Call.ByID(712436855, path).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * Grep searches the files below root, skipping what the file tree skips and
+ * binary files. Matches are streamed to the calling window as EVENT_GREP_MATCHES
+ * while the search runs; cancelling the call stops it.
+ */
+export function Grep(root: string, opts: $models.GrepOptions): $CancellablePromise<$models.GrepSummary> {
+    return $Call.ByID(1869123425, root, opts).then(($result: any) => {
+        return $$createType4($result);
     });
 }
 
+/**
+ * ParseFrontmatter reads the YAML (---) or TOML (+++) frontmatter of a note's
+ * content, such as an unsaved buffer.
+ */
+export function ParseFrontmatter(content: string): $CancellablePromise<frontmatter$0.Frontmatter> {
+    return $Call.ByID(1652393954, content).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * PreviewReplace returns the lines that replacing opts.Search with
+ * opts.Replacement below root would change, without changing anything.
+ */
+export function PreviewReplace(root: string, opts: $models.ReplaceOptions): $CancellablePromise<$models.ReplacePreview> {
+    return $Call.ByID(1550598081, root, opts).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * ReplaceHistory lists the replacements of root that can be undone, newest first.
+ */
+export function ReplaceHistory(root: string): $CancellablePromise<$models.ReplaceRecord[]> {
+    return $Call.ByID(4045033069, root).then(($result: any) => {
+        return $$createType6($result);
+    });
+}
+
+/**
+ * SaveDirOrder writes the override for dir. An empty order removes the file.
+ */
+export function SaveDirOrder(dir: string, order: $models.DirOrder): $CancellablePromise<void> {
+    return $Call.ByID(2117959233, dir, order);
+}
+
 export function SaveFileData(path: string, content: string): $CancellablePromise<void> {
     return $Call.ByID(2148566318, path, content);
 }
 
+/**
+ * SetSortMode changes the order used by GetFileTree for folders without an override.
+ */
+export function SetSortMode(mode: $models.SortMode): $CancellablePromise<void> {
+    return $Call.ByID(977948724, mode);
+}
+
+/**
+ * UndoReplace restores the files changed by the replacement id, all or none.
+ * It fails if any of them was edited after the replacement.
+ */
+export function UndoReplace(root: string, id: string): $CancellablePromise<$models.ReplaceRecord> {
+    return $Call.ByID(2596155129, root, id).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * UpdateFrontmatter edits the frontmatter of the note at path on disk and
+ * returns its new content.
+ */
+export function UpdateFrontmatter(path: string, edits: frontmatter$0.Edit[]): $CancellablePromise<string> {
+    return $Call.ByID(3348490474, path, edits);
+}
+
 // Private type creation functions
-const $$createType0 = $models.Node.createFrom;
+const $$createType0 = $models.ReplaceRecord.createFrom;
+const $$createType1 = $models.DirOrder.createFrom;
+const $$createType2 = $models.Node.createFrom;
+const $$createType3 = frontmatter$0.Frontmatter.createFrom;
+const $$createType4 = $models.GrepSummary.createFrom;
+const $$createType5 = $models.ReplacePreview.createFrom;
+const $$createType6 = $Create.Array($$createType0);
@@ -0,0 +1,110 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Edit sets or removes one top-level key.
+ */
+export class Edit {
+    "key": string;
+    "value": any;
+
+    /**
+     * Remove deletes the key instead of setting it to Value.
+     */
+    "remove": boolean;
+
+    /** Creates a new Edit instance. */
+    constructor($$source: Partial<Edit> = {}) {
+        if (!("key" in $$source)) {
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            this["value"] = null;
+        }
+        if (!("remove" in $$source)) {
+            this["remove"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Edit instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Edit {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Edit($$parsedSource as Partial<Edit>);
+    }
+}
+
+export enum Format {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * FormatYAML is frontmatter between --- lines.
+     */
+    FormatYAML = "yaml",
+
+    /**
+     * FormatTOML is frontmatter between +++ lines.
+     */
+    FormatTOML = "toml",
+};
+
+/**
+ * Frontmatter is the metadata block at the top of a note.
+ */
+export class Frontmatter {
+    /**
+     * Format is empty when the note has no frontmatter.
+     */
+    "format": Format;
+    "data": { [_: string]: any };
+
+    /**
+     * Keys are the top-level keys in the order they are written.
+     */
+    "keys": string[];
+
+    /** Creates a new Frontmatter instance. */
+    constructor($$source: Partial<Frontmatter> = {}) {
+        if (!("format" in $$source)) {
+            this["format"] = Format.$zero;
+        }
+        if (!("data" in $$source)) {
+            this["data"] = {};
+        }
+        if (!("keys" in $$source)) {
+            this["keys"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Frontmatter instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Frontmatter {
+        const $$createField1_0 = $$createType0;
+        const $$createField2_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("data" in $$parsedSource) {
+            $$parsedSource["data"] = $$createField1_0($$parsedSource["data"]);
+        }
+        if ("keys" in $$parsedSource) {
+            $$parsedSource["keys"] = $$createField2_0($$parsedSource["keys"]);
+        }
+        return new Frontmatter($$parsedSource as Partial<Frontmatter>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Map($Create.Any, $Create.Any);
+const $$createType1 = $Create.Array($Create.Any);
@@ -0,0 +1,357 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Edge joins two nodes by ID. Link and embed edges point from the note with the
+ * link to the linked note; tag edges go from the lower to the higher ID.
+ */
+export class Edge {
+    "source": string;
+    "target": string;
+    "kind": EdgeKind;
+
+    /**
+     * Weight counts the links, or the shared tags, behind the edge.
+     */
+    "weight": number;
+
+    /**
+     * Tags are the shared tags of a tag edge.
+     */
+    "tags"?: string[];
+
+    /** Creates a new Edge instance. */
+    constructor($$source: Partial<Edge> = {}) {
+        if (!("source" in $$source)) {
+            this["source"] = "";
+        }
+        if (!("target" in $$source)) {
+            this["target"] = "";
+        }
+        if (!("kind" in $$source)) {
+            this["kind"] = EdgeKind.$zero;
+        }
+        if (!("weight" in $$source)) {
+            this["weight"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Edge instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Edge {
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField4_0($$parsedSource["tags"]);
+        }
+        return new Edge($$parsedSource as Partial<Edge>);
+    }
+}
+
+export enum EdgeKind {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    EdgeLink = "link",
+    EdgeEmbed = "embed",
+
+    /**
+     * EdgeTag joins two notes that share tags.
+     */
+    EdgeTag = "tag",
+};
+
+export class Graph {
+    "nodes": Node[];
+    "edges": Edge[];
+
+    /**
+     * Truncated is set when notes were left out to stay within the limit.
+     */
+    "truncated": boolean;
+
+    /** Creates a new Graph instance. */
+    constructor($$source: Partial<Graph> = {}) {
+        if (!("nodes" in $$source)) {
+            this["nodes"] = [];
+        }
+        if (!("edges" in $$source)) {
+            this["edges"] = [];
+        }
+        if (!("truncated" in $$source)) {
+            this["truncated"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Graph instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Graph {
+        const $$createField0_0 = $$createType2;
+        const $$createField1_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("nodes" in $$parsedSource) {
+            $$parsedSource["nodes"] = $$createField0_0($$parsedSource["nodes"]);
+        }
+        if ("edges" in $$parsedSource) {
+            $$parsedSource["edges"] = $$createField1_0($$parsedSource["edges"]);
+        }
+        return new Graph($$parsedSource as Partial<Graph>);
+    }
+}
+
+export class GraphOptions {
+    /**
+     * Folder keeps the notes below this absolute path.
+     */
+    "folder": string;
+
+    /**
+     * Tags keeps the notes with any of these tags or tags nested below them.
+     */
+    "tags": string[];
+
+    /**
+     * Focus is the absolute path of a note to center the graph on. Only notes
+     * within Depth links of it, in either direction, are kept.
+     */
+    "focus": string;
+
+    /**
+     * Depth defaults to GRAPH_DEPTH.
+     */
+    "depth": number;
+
+    /**
+     * TagEdges adds edges between notes that share tags.
+     */
+    "tagEdges": boolean;
+
+    /**
+     * Limit defaults to GRAPH_LIMIT.
+     */
+    "limit": number;
+
+    /** Creates a new GraphOptions instance. */
+    constructor($$source: Partial<GraphOptions> = {}) {
+        if (!("folder" in $$source)) {
+            this["folder"] = "";
+        }
+        if (!("tags" in $$source)) {
+            this["tags"] = [];
+        }
+        if (!("focus" in $$source)) {
+            this["focus"] = "";
+        }
+        if (!("depth" in $$source)) {
+            this["depth"] = 0;
+        }
+        if (!("tagEdges" in $$source)) {
+            this["tagEdges"] = false;
+        }
+        if (!("limit" in $$source)) {
+            this["limit"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GraphOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GraphOptions {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField1_0($$parsedSource["tags"]);
+        }
+        return new GraphOptions($$parsedSource as Partial<GraphOptions>);
+    }
+}
+
+export enum Kind {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * KindMarkdown is an inline link or image: [text](target "title").
+     */
+    KindMarkdown = "markdown",
+
+    /**
+     * KindWiki is a [[target#heading|alias]] link, or ![[target]] embed.
+     */
+    KindWiki = "wiki",
+};
+
+/**
+ * Link is a link from one note to a file of the same notespace.
+ */
+export class Link {
+    "kind": Kind;
+
+    /**
+     * Embed is set for images and ![[embeds]].
+     */
+    "embed": boolean;
+
+    /**
+     * Line and Column are 1-based; Column counts characters.
+     */
+    "line": number;
+    "column": number;
+
+    /**
+     * Text is the link text or wiki-link alias.
+     */
+    "text": string;
+
+    /**
+     * Target is the link destination as written.
+     */
+    "target": string;
+    "heading"?: string;
+
+    /**
+     * Block is the id of a ^block anchor, without the caret.
+     */
+    "block"?: string;
+
+    /**
+     * Rel is the linked file relative to the root, slash-separated. Markdown
+     * links are resolved by Parse; wiki links need the file list and are
+     * resolved by the Index. Empty when the target is not found.
+     */
+    "rel": string;
+
+    /**
+     * Path and Source are the absolute paths of the linked file and of the note
+     * containing the link. They are set by the Index.
+     */
+    "path": string;
+    "source": string;
+
+    /** Creates a new Link instance. */
+    constructor($$source: Partial<Link> = {}) {
+        if (!("kind" in $$source)) {
+            this["kind"] = Kind.$zero;
+        }
+        if (!("embed" in $$source)) {
+            this["embed"] = false;
+        }
+        if (!("line" in $$source)) {
+            this["line"] = 0;
+        }
+        if (!("column" in $$source)) {
+            this["column"] = 0;
+        }
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+        if (!("target" in $$source)) {
+            this["target"] = "";
+        }
+        if (!("rel" in $$source)) {
+            this["rel"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("source" in $$source)) {
+            this["source"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Link instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Link {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Link($$parsedSource as Partial<Link>);
+    }
+}
+
+/**
+ * Node is one note of the graph.
+ */
+export class Node {
+    /**
+     * ID is the path relative to the root, slash-separated.
+     */
+    "id": string;
+    "path": string;
+
+    /**
+     * Title is the frontmatter title, or the file name without extension.
+     */
+    "title": string;
+
+    /**
+     * Folder is relative to the root and empty for notes at the root.
+     */
+    "folder": string;
+    "tags": string[];
+
+    /**
+     * Degree is the number of link and embed edges of the node in this graph.
+     */
+    "degree": number;
+
+    /** Creates a new Node instance. */
+    constructor($$source: Partial<Node> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("folder" in $$source)) {
+            this["folder"] = "";
+        }
+        if (!("tags" in $$source)) {
+            this["tags"] = [];
+        }
+        if (!("degree" in $$source)) {
+            this["degree"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Node instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Node {
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField4_0($$parsedSource["tags"]);
+        }
+        return new Node($$parsedSource as Partial<Node>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = Node.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = Edge.createFrom;
+const $$createType4 = $Create.Array($$createType3);
@@ -0,0 +1,131 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+export class Group {
+    "key": string;
+    "rows": Row[];
+
+    /** Creates a new Group instance. */
+    constructor($$source: Partial<Group> = {}) {
+        if (!("key" in $$source)) {
+            this["key"] = "";
+        }
+        if (!("rows" in $$source)) {
+            this["rows"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Group instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Group {
+        const $$createField1_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("rows" in $$parsedSource) {
+            $$parsedSource["rows"] = $$createField1_0($$parsedSource["rows"]);
+        }
+        return new Group($$parsedSource as Partial<Group>);
+    }
+}
+
+/**
+ * Result is a query rendered as a table.
+ */
+export class Result {
+    /**
+     * Columns name the cells of each row; the first is always the note.
+     */
+    "columns": string[];
+
+    /**
+     * Groups hold the rows in order. A query without GROUP BY has one group
+     * with an empty key.
+     */
+    "groups": Group[];
+
+    /**
+     * Total counts the notes that matched, before LIMIT.
+     */
+    "total": number;
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("columns" in $$source)) {
+            this["columns"] = [];
+        }
+        if (!("groups" in $$source)) {
+            this["groups"] = [];
+        }
+        if (!("total" in $$source)) {
+            this["total"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        const $$createField0_0 = $$createType2;
+        const $$createField1_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("columns" in $$parsedSource) {
+            $$parsedSource["columns"] = $$createField0_0($$parsedSource["columns"]);
+        }
+        if ("groups" in $$parsedSource) {
+            $$parsedSource["groups"] = $$createField1_0($$parsedSource["groups"]);
+        }
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
+
+export class Row {
+    "path": string;
+    "title": string;
+
+    /**
+     * Cells are the values of the columns after the note, as text.
+     */
+    "cells": string[];
+
+    /** Creates a new Row instance. */
+    constructor($$source: Partial<Row> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("cells" in $$source)) {
+            this["cells"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Row instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Row {
+        const $$createField2_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("cells" in $$parsedSource) {
+            $$parsedSource["cells"] = $$createField2_0($$parsedSource["cells"]);
+        }
+        return new Row($$parsedSource as Partial<Row>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Row.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $Create.Array($Create.Any);
+const $$createType3 = Group.createFrom;
+const $$createType4 = $Create.Array($$createType3);
@@ -0,0 +1,154 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * FileMatch is a file whose path or title matched a query. Positions are the
+ * matched characters of Rel, TitlePositions those of Title.
+ */
+export class FileMatch {
+    "path": string;
+    "rel": string;
+    "title"?: string;
+    "score": number;
+    "positions": number[];
+    "titlePositions": number[];
+    "recent": boolean;
+
+    /** Creates a new FileMatch instance. */
+    constructor($$source: Partial<FileMatch> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("rel" in $$source)) {
+            this["rel"] = "";
+        }
+        if (!("score" in $$source)) {
+            this["score"] = 0;
+        }
+        if (!("positions" in $$source)) {
+            this["positions"] = [];
+        }
+        if (!("titlePositions" in $$source)) {
+            this["titlePositions"] = [];
+        }
+        if (!("recent" in $$source)) {
+            this["recent"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FileMatch instance from a string or object.
+     */
+    static createFrom($$source: any = {}): FileMatch {
+        const $$createField4_0 = $$createType0;
+        const $$createField5_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("positions" in $$parsedSource) {
+            $$parsedSource["positions"] = $$createField4_0($$parsedSource["positions"]);
+        }
+        if ("titlePositions" in $$parsedSource) {
+            $$parsedSource["titlePositions"] = $$createField5_0($$parsedSource["titlePositions"]);
+        }
+        return new FileMatch($$parsedSource as Partial<FileMatch>);
+    }
+}
+
+export class Part {
+    "text": string;
+    "match"?: boolean;
+
+    /** Creates a new Part instance. */
+    constructor($$source: Partial<Part> = {}) {
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Part instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Part {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Part($$parsedSource as Partial<Part>);
+    }
+}
+
+export class Result {
+    "path": string;
+    "score": number;
+    "snippets": Snippet[];
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("score" in $$source)) {
+            this["score"] = 0;
+        }
+        if (!("snippets" in $$source)) {
+            this["snippets"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        const $$createField2_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("snippets" in $$parsedSource) {
+            $$parsedSource["snippets"] = $$createField2_0($$parsedSource["snippets"]);
+        }
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
+
+/**
+ * Snippet is a line of a result split into the parts that matched and those that did not.
+ */
+export class Snippet {
+    "line": number;
+    "parts": Part[];
+
+    /** Creates a new Snippet instance. */
+    constructor($$source: Partial<Snippet> = {}) {
+        if (!("line" in $$source)) {
+            this["line"] = 0;
+        }
+        if (!("parts" in $$source)) {
+            this["parts"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Snippet instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Snippet {
+        const $$createField1_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("parts" in $$parsedSource) {
+            $$parsedSource["parts"] = $$createField1_0($$parsedSource["parts"]);
+        }
+        return new Snippet($$parsedSource as Partial<Snippet>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = Snippet.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = Part.createFrom;
+const $$createType4 = $Create.Array($$createType3);
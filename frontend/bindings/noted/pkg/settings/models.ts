@@ -0,0 +1,237 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as file$0 from "../file/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../time/models.js";
+
+/**
+ * Bounds is the position and size of a window in screen coordinates.
+ */
+export class Bounds {
+    "x": number;
+    "y": number;
+    "width": number;
+    "height": number;
+    "maximised": boolean;
+
+    /**
+     * Screen is the ID of the display the window was on.
+     */
+    "screen"?: string;
+
+    /** Creates a new Bounds instance. */
+    constructor($$source: Partial<Bounds> = {}) {
+        if (!("x" in $$source)) {
+            this["x"] = 0;
+        }
+        if (!("y" in $$source)) {
+            this["y"] = 0;
+        }
+        if (!("width" in $$source)) {
+            this["width"] = 0;
+        }
+        if (!("height" in $$source)) {
+            this["height"] = 0;
+        }
+        if (!("maximised" in $$source)) {
+            this["maximised"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Bounds instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Bounds {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Bounds($$parsedSource as Partial<Bounds>);
+    }
+}
+
+/**
+ * Preferences are app-wide and apply to every notespace.
+ */
+export class Preferences {
+    /**
+     * MaxRecents limits the unpinned recents; pinned entries are always kept.
+     */
+    "maxRecents": number;
+
+    /**
+     * SortMode is the default file tree order (see file.SortMode).
+     */
+    "sortMode": file$0.SortMode;
+
+    /**
+     * StartFresh opens the directory picker on launch instead of the previous session.
+     */
+    "startFresh": boolean;
+
+    /** Creates a new Preferences instance. */
+    constructor($$source: Partial<Preferences> = {}) {
+        if (!("maxRecents" in $$source)) {
+            this["maxRecents"] = 0;
+        }
+        if (!("sortMode" in $$source)) {
+            this["sortMode"] = file$0.SortMode.$zero;
+        }
+        if (!("startFresh" in $$source)) {
+            this["startFresh"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Preferences instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Preferences {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Preferences($$parsedSource as Partial<Preferences>);
+    }
+}
+
+export class Recent {
+    "path": string;
+    "pinned": boolean;
+    "openedAt": time$0.Time;
+
+    /** Creates a new Recent instance. */
+    constructor($$source: Partial<Recent> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("pinned" in $$source)) {
+            this["pinned"] = false;
+        }
+        if (!("openedAt" in $$source)) {
+            this["openedAt"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Recent instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Recent {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Recent($$parsedSource as Partial<Recent>);
+    }
+}
+
+/**
+ * SessionWindow is an editor window that was open when the app last quit. Its
+ * tabs are kept in the notespace's workspace state, not here.
+ */
+export class SessionWindow {
+    "path": string;
+    "bounds": Bounds;
+
+    /**
+     * ReadOnly windows refuse every change to the notespace.
+     */
+    "readOnly"?: boolean;
+
+    /** Creates a new SessionWindow instance. */
+    constructor($$source: Partial<SessionWindow> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("bounds" in $$source)) {
+            this["bounds"] = (new Bounds());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SessionWindow instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SessionWindow {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("bounds" in $$parsedSource) {
+            $$parsedSource["bounds"] = $$createField1_0($$parsedSource["bounds"]);
+        }
+        return new SessionWindow($$parsedSource as Partial<SessionWindow>);
+    }
+}
+
+export class Settings {
+    "version": number;
+    "preferences": Preferences;
+    "recents": Recent[];
+
+    /**
+     * Session lists the editor windows that were open when the app last quit.
+     */
+    "session": SessionWindow[];
+
+    /**
+     * Picker is the last position of the directory picker window.
+     */
+    "picker": Bounds;
+
+    /** Creates a new Settings instance. */
+    constructor($$source: Partial<Settings> = {}) {
+        if (!("version" in $$source)) {
+            this["version"] = 0;
+        }
+        if (!("preferences" in $$source)) {
+            this["preferences"] = (new Preferences());
+        }
+        if (!("recents" in $$source)) {
+            this["recents"] = [];
+        }
+        if (!("session" in $$source)) {
+            this["session"] = [];
+        }
+        if (!("picker" in $$source)) {
+            this["picker"] = (new Bounds());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Settings instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Settings {
+        const $$createField1_0 = $$createType1;
+        const $$createField2_0 = $$createType3;
+        const $$createField3_0 = $$createType5;
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("preferences" in $$parsedSource) {
+            $$parsedSource["preferences"] = $$createField1_0($$parsedSource["preferences"]);
+        }
+        if ("recents" in $$parsedSource) {
+            $$parsedSource["recents"] = $$createField2_0($$parsedSource["recents"]);
+        }
+        if ("session" in $$parsedSource) {
+            $$parsedSource["session"] = $$createField3_0($$parsedSource["session"]);
+        }
+        if ("picker" in $$parsedSource) {
+            $$parsedSource["picker"] = $$createField4_0($$parsedSource["picker"]);
+        }
+        return new Settings($$parsedSource as Partial<Settings>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Bounds.createFrom;
+const $$createType1 = Preferences.createFrom;
+const $$createType2 = Recent.createFrom;
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = SessionWindow.createFrom;
+const $$createType5 = $Create.Array($$createType4);
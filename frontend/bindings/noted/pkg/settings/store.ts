@@ -0,0 +1,113 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+/**
+ * Store keeps Settings in memory and writes every change through to disk.
+ * @module
+ */
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * AddRecent marks path as just opened.
+ */
+export function AddRecent(path: string): $CancellablePromise<$models.Recent[]> {
+    return $Call.ByID(1192038183, path).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * ForgetMissing removes recents whose folder no longer exists. Pinned entries are kept.
+ */
+export function ForgetMissing(): $CancellablePromise<$models.Recent[]> {
+    return $Call.ByID(1979496708).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * GetPickerBounds returns where the directory picker window was last placed.
+ */
+export function GetPickerBounds(): $CancellablePromise<$models.Bounds> {
+    return $Call.ByID(264137016).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+/**
+ * GetRecents returns pinned notespaces first, then the most recently opened.
+ */
+export function GetRecents(): $CancellablePromise<$models.Recent[]> {
+    return $Call.ByID(1870048829).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * GetSession returns the editor windows to reopen on launch, in the order they were opened.
+ */
+export function GetSession(): $CancellablePromise<$models.SessionWindow[]> {
+    return $Call.ByID(459077625).then(($result: any) => {
+        return $$createType4($result);
+    });
+}
+
+export function GetSettings(): $CancellablePromise<$models.Settings> {
+    return $Call.ByID(1135589602).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * ImportRecents adds paths that are not known yet, oldest last. It is used to
+ * move the list the frontend used to keep in localStorage.
+ */
+export function ImportRecents(paths: string[]): $CancellablePromise<$models.Recent[]> {
+    return $Call.ByID(4070825244, paths).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function PinRecent(path: string, pinned: boolean): $CancellablePromise<$models.Recent[]> {
+    return $Call.ByID(4124721383, path, pinned).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function RemoveRecent(path: string): $CancellablePromise<$models.Recent[]> {
+    return $Call.ByID(2489010558, path).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function SavePickerBounds(bounds: $models.Bounds): $CancellablePromise<void> {
+    return $Call.ByID(337560083, bounds);
+}
+
+/**
+ * SaveSession replaces the remembered editor windows.
+ */
+export function SaveSession(windows: $models.SessionWindow[]): $CancellablePromise<void> {
+    return $Call.ByID(43655708, windows);
+}
+
+export function UpdatePreferences(preferences: $models.Preferences): $CancellablePromise<$models.Settings> {
+    return $Call.ByID(1039173246, preferences).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = $models.Recent.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $models.Bounds.createFrom;
+const $$createType3 = $models.SessionWindow.createFrom;
+const $$createType4 = $Create.Array($$createType3);
+const $$createType5 = $models.Settings.createFrom;
@@ -0,0 +1,103 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Note is a note selected by a tag query.
+ */
+export class Note {
+    "path": string;
+    "tags": string[];
+
+    /** Creates a new Note instance. */
+    constructor($$source: Partial<Note> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("tags" in $$source)) {
+            this["tags"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Note instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Note {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField1_0($$parsedSource["tags"]);
+        }
+        return new Note($$parsedSource as Partial<Note>);
+    }
+}
+
+/**
+ * Tag is one level of the tag hierarchy: #project/alpha is the tag alpha below
+ * project.
+ */
+export class Tag {
+    /**
+     * Name is the full tag without the hash, as first spelled in the notespace.
+     */
+    "name": string;
+
+    /**
+     * Label is the last part of the name.
+     */
+    "label": string;
+
+    /**
+     * Count is the number of notes with exactly this tag.
+     */
+    "count": number;
+
+    /**
+     * Total is the number of notes with this tag or one nested below it.
+     */
+    "total": number;
+    "children": Tag[];
+
+    /** Creates a new Tag instance. */
+    constructor($$source: Partial<Tag> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("count" in $$source)) {
+            this["count"] = 0;
+        }
+        if (!("total" in $$source)) {
+            this["total"] = 0;
+        }
+        if (!("children" in $$source)) {
+            this["children"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Tag instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Tag {
+        const $$createField4_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("children" in $$parsedSource) {
+            $$parsedSource["children"] = $$createField4_0($$parsedSource["children"]);
+        }
+        return new Tag($$parsedSource as Partial<Tag>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = Tag.createFrom;
+const $$createType2 = $Create.Array($$createType1);
@@ -0,0 +1,161 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as settings$0 from "../settings/models.js";
+
+/**
+ * Cursor is a text selection in a note.
+ */
+export class Cursor {
+    "start": number;
+    "end": number;
+
+    /** Creates a new Cursor instance. */
+    constructor($$source: Partial<Cursor> = {}) {
+        if (!("start" in $$source)) {
+            this["start"] = 0;
+        }
+        if (!("end" in $$source)) {
+            this["end"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Cursor instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Cursor {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Cursor($$parsedSource as Partial<Cursor>);
+    }
+}
+
+/**
+ * Patch holds the fields to change. Nil fields are left as they are; cursors
+ * are merged into the existing ones and a nil cursor removes the entry.
+ */
+export class Patch {
+    "tabs"?: string[] | null;
+    "activeTab"?: string | null;
+    "bookmarks"?: string[] | null;
+    "cursors"?: { [_: string]: Cursor | null };
+    "window"?: settings$0.Bounds | null;
+
+    /** Creates a new Patch instance. */
+    constructor($$source: Partial<Patch> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Patch instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Patch {
+        const $$createField0_0 = $$createType1;
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType4;
+        const $$createField4_0 = $$createType6;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tabs" in $$parsedSource) {
+            $$parsedSource["tabs"] = $$createField0_0($$parsedSource["tabs"]);
+        }
+        if ("bookmarks" in $$parsedSource) {
+            $$parsedSource["bookmarks"] = $$createField2_0($$parsedSource["bookmarks"]);
+        }
+        if ("cursors" in $$parsedSource) {
+            $$parsedSource["cursors"] = $$createField3_0($$parsedSource["cursors"]);
+        }
+        if ("window" in $$parsedSource) {
+            $$parsedSource["window"] = $$createField4_0($$parsedSource["window"]);
+        }
+        return new Patch($$parsedSource as Partial<Patch>);
+    }
+}
+
+/**
+ * State is what a notespace remembers between launches. Paths are absolute in
+ * the API and stored relative to the notespace root, so the state moves with the folder.
+ */
+export class State {
+    "version": number;
+    "tabs": string[];
+    "activeTab"?: string;
+    "bookmarks": string[];
+    "cursors": { [_: string]: Cursor };
+
+    /**
+     * Recent lists the notes last made active, most recent first.
+     */
+    "recent": string[];
+
+    /**
+     * Window is where the notespace window was last placed on this machine.
+     */
+    "window"?: settings$0.Bounds | null;
+
+    /** Creates a new State instance. */
+    constructor($$source: Partial<State> = {}) {
+        if (!("version" in $$source)) {
+            this["version"] = 0;
+        }
+        if (!("tabs" in $$source)) {
+            this["tabs"] = [];
+        }
+        if (!("bookmarks" in $$source)) {
+            this["bookmarks"] = [];
+        }
+        if (!("cursors" in $$source)) {
+            this["cursors"] = {};
+        }
+        if (!("recent" in $$source)) {
+            this["recent"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new State instance from a string or object.
+     */
+    static createFrom($$source: any = {}): State {
+        const $$createField1_0 = $$createType0;
+        const $$createField3_0 = $$createType0;
+        const $$createField4_0 = $$createType7;
+        const $$createField5_0 = $$createType0;
+        const $$createField6_0 = $$createType6;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tabs" in $$parsedSource) {
+            $$parsedSource["tabs"] = $$createField1_0($$parsedSource["tabs"]);
+        }
+        if ("bookmarks" in $$parsedSource) {
+            $$parsedSource["bookmarks"] = $$createField3_0($$parsedSource["bookmarks"]);
+        }
+        if ("cursors" in $$parsedSource) {
+            $$parsedSource["cursors"] = $$createField4_0($$parsedSource["cursors"]);
+        }
+        if ("recent" in $$parsedSource) {
+            $$parsedSource["recent"] = $$createField5_0($$parsedSource["recent"]);
+        }
+        if ("window" in $$parsedSource) {
+            $$parsedSource["window"] = $$createField6_0($$parsedSource["window"]);
+        }
+        return new State($$parsedSource as Partial<State>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = $Create.Nullable($$createType0);
+const $$createType2 = Cursor.createFrom;
+const $$createType3 = $Create.Nullable($$createType2);
+const $$createType4 = $Create.Map($Create.Any, $$createType3);
+const $$createType5 = settings$0.Bounds.createFrom;
+const $$createType6 = $Create.Nullable($$createType5);
+const $$createType7 = $Create.Map($Create.Any, $$createType2);
@@ -22,21 +22,18 @@ export const editorSettings = new Command({
   shortcut: ["Meta", ","],
 });
 
-// ! Unhandled ❌
+// * Handled ✔︎
 export const editorNotespaceFileOpen = new Command({
   id: "editor.notespace.file.open",
   label: "Go to File",
   shortcut: ["Meta", "P"],
 });
 
-// ! Unhandled ❌
+// * Handled ✔︎
 export const editorNotespaceFind = new Command({
   id: "editor.notespace.find",
   label: "Search in Notespace",
   shortcut: ["Meta", "Shift", "F"],
-  // handler: ({ state, setState }) => {
-  //   setState(disableWhenDialog(state, { ...state, sidebar_tab: "grep" }));
-  // },
 });
 
 // ! Unhandled ❌
@@ -71,3 +68,17 @@ export const editorNotespaceFileSave = new Command({
   label: "Create New File",
   shortcut: ["Meta", "S"],
 });
+
+// * Handled ✔︎
+export const editorNotespaceDoctor = new Command({
+  id: "editor.notespace.doctor",
+  label: "Check Notespace for Problems",
+  shortcut: [],
+});
+
+// * Handled ✔︎
+export const editorNotespaceLinks = new Command({
+  id: "editor.notespace.links",
+  label: "Check for Broken Links",
+  shortcut: [],
+});
@@ -0,0 +1,149 @@
+import { useCallback, useEffect, useState } from "react";
+import { useNavigate } from "react-router";
+import { CancelError } from "@wailsio/runtime";
+import type { FileMatch } from "@go/noted/pkg/search";
+import { useStore } from "../store";
+import {
+  CommandDialog,
+  CommandEmpty,
+  CommandGroup,
+  CommandInput,
+  CommandItem,
+  CommandList,
+} from "../ui/command";
+import { commands } from "@/command";
+import { toastifyError } from "@/utils/constants/status-codes";
+
+// Matching is fast; only skip the backend while keys are held down.
+const FIND_DELAY = 50;
+
+// Positions are in characters, which differ from string indices outside the BMP.
+const Highlighted = ({
+  text,
+  positions,
+  className,
+}: {
+  text: string;
+  positions: Array<number>;
+  className?: string;
+}) => {
+  const matched = new Set(positions);
+  return (
+    <span className={className}>
+      {Array.from(text).map((char, i) =>
+        matched.has(i) ? (
+          <mark key={i} className="bg-transparent font-semibold text-text">
+            {char}
+          </mark>
+        ) : (
+          char
+        ),
+      )}
+    </span>
+  );
+};
+
+export function GoToFile() {
+  const { state, setState, services } = useStore();
+  const navigate = useNavigate();
+  const [query, setQuery] = useState("");
+  const [matches, setMatches] = useState<Array<FileMatch>>([]);
+
+  const open = state.dialog === "go-to-file";
+  const setOpen = (value: boolean) => {
+    setState("dialog", value ? "go-to-file" : null);
+  };
+
+  const handler = useCallback(
+    () =>
+      setState({
+        ...state,
+        dialog: state.dialog === "go-to-file" ? null : "go-to-file",
+      }),
+    [state],
+  );
+
+  useEffect(() => {
+    const cmd = commands.editorNotespaceFileOpen.subscribe(handler);
+
+    return () => {
+      cmd.unsubscribe();
+    };
+  }, [handler]);
+
+  useEffect(() => {
+    if (!open) {
+      setQuery("");
+      setMatches([]);
+      return;
+    }
+
+    let request: ReturnType<typeof services.notespace.findFiles> | undefined;
+    const timeout = setTimeout(() => {
+      request = services.notespace.findFiles(query);
+      request.then(setMatches).catch((error) => {
+        if (!(error instanceof CancelError)) toastifyError(error);
+      });
+    }, FIND_DELAY);
+
+    return () => {
+      clearTimeout(timeout);
+      request?.cancel();
+    };
+  }, [open, query, services.notespace]);
+
+  return (
+    <CommandDialog
+      className="select-none"
+      title="Go to File"
+      description="Find a file of this notespace by name..."
+      modal
+      shouldFilter={false}
+      open={open}
+      onOpenChange={setOpen}
+    >
+      <CommandInput
+        placeholder="Go to file..."
+        value={query}
+        onValueChange={setQuery}
+      />
+      <CommandEmpty>No matching files</CommandEmpty>
+      <CommandList>
+        <CommandGroup>
+          {matches.map((match) => (
+            <CommandItem
+              key={match.path}
+              value={match.path}
+              onSelect={() => {
+                setOpen(false);
+                navigate({
+                  pathname: "/editor",
+                  search: `?root=${state.root}&file=${match.path}`,
+                });
+              }}
+            >
+              <div className="flex flex-col min-w-0">
+                {match.title && (
+                  <Highlighted
+                    className="truncate"
+                    text={match.title}
+                    positions={match.titlePositions}
+                  />
+                )}
+                <Highlighted
+                  className={
+                    match.title
+                      ? "truncate text-mini text-text-muted"
+                      : "truncate"
+                  }
+                  text={match.rel}
+                  positions={match.positions}
+                />
+              </div>
+            </CommandItem>
+          ))}
+        </CommandGroup>
+      </CommandList>
+    </CommandDialog>
+  );
+}
@@ -0,0 +1,130 @@
+import { useCallback, useEffect, useState } from "react";
+import { useNavigate } from "react-router";
+import { CancelError } from "@wailsio/runtime";
+import type { Result } from "@go/noted/pkg/search";
+import { useStore } from "../store";
+import {
+  CommandDialog,
+  CommandEmpty,
+  CommandGroup,
+  CommandInput,
+  CommandItem,
+  CommandList,
+} from "../ui/command";
+import { commands } from "@/command";
+import { toastifyError } from "@/utils/constants/status-codes";
+
+// Wait for a pause in typing before asking the backend.
+const SEARCH_DELAY = 150;
+
+export function SearchNotespace() {
+  const { state, setState, services } = useStore();
+  const navigate = useNavigate();
+  const [query, setQuery] = useState("");
+  const [results, setResults] = useState<Array<Result>>([]);
+
+  const open = state.dialog === "search-notespace";
+  const setOpen = (value: boolean) => {
+    setState("dialog", value ? "search-notespace" : null);
+  };
+
+  const handler = useCallback(
+    () =>
+      setState({
+        ...state,
+        dialog: state.dialog === "search-notespace" ? null : "search-notespace",
+      }),
+    [state],
+  );
+
+  useEffect(() => {
+    const cmd = commands.editorNotespaceFind.subscribe(handler);
+
+    return () => {
+      cmd.unsubscribe();
+    };
+  }, [handler]);
+
+  useEffect(() => {
+    if (!open || !query.trim()) {
+      setResults([]);
+      return;
+    }
+
+    let request: ReturnType<typeof services.notespace.search> | undefined;
+    const timeout = setTimeout(() => {
+      request = services.notespace.search(query);
+      request.then(setResults).catch((error) => {
+        if (!(error instanceof CancelError)) toastifyError(error);
+      });
+    }, SEARCH_DELAY);
+
+    return () => {
+      clearTimeout(timeout);
+      request?.cancel();
+    };
+  }, [open, query, services.notespace]);
+
+  return (
+    <CommandDialog
+      className="select-none"
+      title="Search in Notespace"
+      description="Search the notes of this notespace..."
+      modal
+      shouldFilter={false}
+      open={open}
+      onOpenChange={setOpen}
+    >
+      <CommandInput
+        placeholder='Search notes... ("phrase", prefix*, -exclude)'
+        value={query}
+        onValueChange={setQuery}
+      />
+      <CommandEmpty>
+        {query.trim() ? "No matches" : "Type to search"}
+      </CommandEmpty>
+      <CommandList>
+        <CommandGroup>
+          {results.map((result) => (
+            <CommandItem
+              key={result.path}
+              value={result.path}
+              onSelect={() => {
+                setOpen(false);
+                navigate({
+                  pathname: "/editor",
+                  search: `?root=${state.root}&file=${result.path}`,
+                });
+              }}
+            >
+              <div className="flex flex-col gap-1 min-w-0">
+                <span className="truncate">
+                  {result.path.replace(`${state.root}/`, "")}
+                </span>
+                {result.snippets.map((snippet) => (
+                  <span
+                    key={snippet.line}
+                    className="truncate text-mini text-text-muted"
+                  >
+                    {snippet.parts.map((part, i) =>
+                      part.match ? (
+                        <mark
+                          key={i}
+                          className="bg-transparent font-semibold text-text"
+                        >
+                          {part.text}
+                        </mark>
+                      ) : (
+                        <span key={i}>{part.text}</span>
+                      ),
+                    )}
+                  </span>
+                ))}
+              </div>
+            </CommandItem>
+          ))}
+        </CommandGroup>
+      </CommandList>
+    </CommandDialog>
+  );
+}
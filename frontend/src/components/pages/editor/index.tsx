@@ -1,5 +1,11 @@
 import { useEffect } from "react";
-import { type LoaderFunctionArgs, Outlet, useLoaderData } from "react-router";
+import { toast } from "sonner";
+import {
+  type LoaderFunctionArgs,
+  Outlet,
+  useLoaderData,
+  useNavigate,
+} from "react-router";
 
 import { useStore } from "@/components/store";
 
@@ -7,24 +13,87 @@ import { Loader } from "@/components/ui/loader";
 import { Sidebar } from "./sidebar";
 import { Textarea } from "./textarea";
 
-import { getServices } from "@/services";
+import { commands } from "@/command";
+import { getServices, type NotespaceService } from "@/services";
 import { getCommandFromEvent } from "@/utils/command-helpers";
+import { toastifyError } from "@/utils/constants/status-codes";
 
 import LogoIcon from "~/images/logo-icon.svg?react";
 
 const EditorContent = () => {
   const loaderData = useLoaderData<LoaderData<typeof EditorContent.loader>>();
   const { state, setState, services } = useStore();
+  const navigate = useNavigate();
 
   useEffect(() => {
     setState({
       config: loaderData.notespace.config,
       root: loaderData.notespace.path,
+      read_only: loaderData.editorState.readOnly,
       notespaces: loaderData.notespaces,
       rootNode: loaderData.rootNode,
+      tabs: loaderData.tabs.paths,
+      bookmarks: loaderData.workspace.bookmarks,
     });
   }, [loaderData]);
 
+  useEffect(() => {
+    // Skip the initial empty state, before the loader data is applied
+    if (!state.root) return;
+    services.notespace
+      .saveOpenTabs(state.tabs, state.active_tab?.path ?? null)
+      .catch((error) => console.log({ error }));
+  }, [state.root, state.tabs, state.active_tab?.path]);
+
+  useEffect(
+    () =>
+      services.notespace.onConfigChanged(({ config }) =>
+        setState("config", config),
+      ),
+    [services.notespace],
+  );
+
+  useEffect(
+    () =>
+      services.notespace.onOpenFile((path) =>
+        navigate({
+          pathname: "/editor",
+          search: `?root=${state.root}&file=${path}`,
+        }),
+      ),
+    [services.notespace, state.root],
+  );
+
+  useEffect(
+    () =>
+      services.notespace.onConfigInvalid((error) =>
+        toast.error("The notespace config file is invalid", {
+          description: error.errors
+            .map(({ field, message, line }) =>
+              [line && `line ${line}`, field, message]
+                .filter(Boolean)
+                .join(": "),
+            )
+            .join("\n"),
+        }),
+      ),
+    [services.notespace],
+  );
+
+  useEffect(() => {
+    const doctor = commands.editorNotespaceDoctor.subscribe(() =>
+      checkNotespace(services.notespace, state.read_only),
+    );
+    const links = commands.editorNotespaceLinks.subscribe(() =>
+      checkNotespace(services.notespace, state.read_only, true),
+    );
+
+    return () => {
+      doctor.unsubscribe();
+      links.unsubscribe();
+    };
+  }, [services.notespace, state.read_only]);
+
   // Key Binding useEffect
   useEffect(() => {
     const down = (e: KeyboardEvent) => {
@@ -48,6 +117,54 @@ const EditorContent = () => {
   );
 };
 
+// Shows the doctor report, or only its broken links, as a toast, offering the
+// automatic fixes if any.
+async function checkNotespace(
+  notespace: NotespaceService,
+  readOnly: boolean,
+  linksOnly = false,
+) {
+  try {
+    const report = linksOnly
+      ? await notespace.checkLinks()
+      : await notespace.checkNotespace();
+    if (report.issues.length === 0) {
+      toast.success(linksOnly ? "No broken links found" : "No problems found");
+      return;
+    }
+
+    const fixable = report.issues.filter((issue) => issue.fix);
+    toast.warning(
+      `${report.issues.length} problem${report.issues.length === 1 ? "" : "s"} found`,
+      {
+        description: report.issues
+          .slice(0, 5)
+          .map(({ path, line, message }) =>
+            [line ? `${path}:${line}` : path, message].join(": "),
+          )
+          .join("\n"),
+        action:
+          fixable.length > 0 && !readOnly
+            ? {
+                label: `Fix ${fixable.length}`,
+                onClick: () =>
+                  notespace
+                    .fixNotespace(fixable.map(({ id }) => id))
+                    .then(({ fixed = [] }) =>
+                      toast.success(
+                        `Fixed ${fixed.length} problem${fixed.length === 1 ? "" : "s"}`,
+                      ),
+                    )
+                    .catch(toastifyError),
+              }
+            : undefined,
+      },
+    );
+  } catch (error) {
+    toastifyError(error);
+  }
+}
+
 const Editor = () => {
   return (
     <div className="flex flex-col h-full items-stretch w-full select-none">
@@ -64,15 +181,28 @@ EditorContent.loader = async ({ request }: LoaderFunctionArgs) => {
 
   const services = getServices(root);
 
-  const [{ config, path }, notespaces, rootNode] = await Promise.all([
+  const [
+    { config, path },
+    notespaces,
+    rootNode,
+    tabs,
+    workspace,
+    editorState,
+  ] = await Promise.all([
     services.notespace.getCurrentNotespace(),
     services.notespace.getRecentNotespaces(),
     services.files?.getFileTree(),
+    services.notespace.getOpenTabs(),
+    services.notespace.getWorkspaceState(),
+    services.notespace.getEditorState(),
   ]);
 
   return {
+    editorState,
     notespaces,
     rootNode,
+    tabs,
+    workspace,
     notespace: { config, path },
   };
 };
@@ -0,0 +1,250 @@
+import { useEffect, useMemo, useState } from "react";
+import { useNavigate } from "react-router";
+import { CancelError } from "@wailsio/runtime";
+
+import { Icon, type IconName } from "@/components/icon";
+import { useStore } from "@/components/store";
+import { cn } from "@/utils/cn";
+import { fromError } from "@/utils/constants/status-codes";
+import { ReplacePreview } from "./replace";
+
+import type { GrepBatch, GrepMatch, GrepSummary } from "@go/noted/pkg/file";
+
+// Wait for a pause in typing before searching.
+const GREP_DELAY = 250;
+
+type Flags = { regex: boolean; caseSensitive: boolean; wholeWord: boolean };
+
+const globs = (value: string) =>
+  value
+    .split(",")
+    .map((glob) => glob.trim())
+    .filter(Boolean);
+
+const FlagButton = ({
+  icon,
+  title,
+  active,
+  onClick,
+}: {
+  icon: IconName;
+  title: string;
+  active: boolean;
+  onClick: () => void;
+}) => (
+  <button
+    title={title}
+    aria-pressed={active}
+    className={cn(
+      "p-1 rounded transition-colors",
+      active ? "text-text bg-surface-muted" : "text-text-muted hover:text-text",
+    )}
+    onClick={onClick}
+  >
+    <Icon name={icon} size={14} strokeWidth={2} />
+  </button>
+);
+
+// Ranges are in characters, which differ from string indices outside the BMP.
+const Line = ({ match }: { match: GrepMatch }) => {
+  const chars = Array.from(match.text);
+  const parts: Array<{ text: string; match: boolean }> = [];
+  let cursor = 0;
+  for (const { start, end } of match.ranges) {
+    if (start > cursor)
+      parts.push({ text: chars.slice(cursor, start).join(""), match: false });
+    parts.push({ text: chars.slice(start, end).join(""), match: true });
+    cursor = end;
+  }
+  parts.push({ text: chars.slice(cursor).join(""), match: false });
+
+  return (
+    <span className="truncate">
+      {parts.map((part, i) =>
+        part.match ? (
+          <mark key={i} className="bg-surface-muted text-text rounded-sm">
+            {part.text}
+          </mark>
+        ) : (
+          <span key={i}>{part.text.trimStart()}</span>
+        ),
+      )}
+    </span>
+  );
+};
+
+export const Grep = () => {
+  const { state, services } = useStore();
+  const navigate = useNavigate();
+
+  const [pattern, setPattern] = useState("");
+  const [flags, setFlags] = useState<Flags>({
+    regex: false,
+    caseSensitive: false,
+    wholeWord: false,
+  });
+  const [include, setInclude] = useState("");
+  const [exclude, setExclude] = useState("");
+  const [replacing, setReplacing] = useState(false);
+  const [replacement, setReplacement] = useState("");
+
+  const [batches, setBatches] = useState<Array<GrepBatch>>([]);
+  const [summary, setSummary] = useState<GrepSummary | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    setBatches([]);
+    setSummary(null);
+    setError(null);
+    if (!pattern || replacing || !services.files) return;
+
+    const files = services.files;
+    const id = crypto.randomUUID();
+    const off = files.onGrepMatches(id, (batch) =>
+      setBatches((prev) => [...prev, batch]),
+    );
+
+    let request: ReturnType<typeof files.grep> | undefined;
+    const timeout = setTimeout(() => {
+      request = files.grep({
+        id,
+        pattern,
+        ...flags,
+        include: globs(include),
+        exclude: globs(exclude),
+        context: 0,
+        maxMatches: 0,
+      });
+      request.then(setSummary).catch((error) => {
+        if (!(error instanceof CancelError))
+          setError(fromError(error)[0].message);
+      });
+    }, GREP_DELAY);
+
+    return () => {
+      clearTimeout(timeout);
+      request?.cancel();
+      off();
+    };
+  }, [pattern, flags, include, exclude, replacing, services.files]);
+
+  const replaceOptions = useMemo(
+    () => ({
+      search: {
+        id: "",
+        pattern,
+        ...flags,
+        include: globs(include),
+        exclude: globs(exclude),
+        context: 0,
+        maxMatches: 0,
+      },
+      replacement,
+    }),
+    [pattern, flags, include, exclude, replacement],
+  );
+
+  const status = useMemo(() => {
+    if (error) return error;
+    if (!pattern) return null;
+    if (!summary) return "Searching...";
+    const found = `${summary.matches} result${summary.matches === 1 ? "" : "s"} in ${summary.matchedFiles} file${summary.matchedFiles === 1 ? "" : "s"}`;
+    return summary.truncated ? `${found} (stopped early)` : found;
+  }, [error, pattern, summary]);
+
+  const toggle = (flag: keyof Flags) =>
+    setFlags((prev) => ({ ...prev, [flag]: !prev[flag] }));
+
+  return (
+    <div className="flex flex-col w-full h-full items-stretch gap-0">
+      <header className="px-4 bg-surface-muted text-display font-semibold">
+        <p className="py-0.5">Search in Files</p>
+      </header>
+      <div className="flex flex-col gap-1 px-4 py-2 text-display">
+        <div className="flex items-center gap-1 border-b border-surface">
+          <input
+            className="flex-1 min-w-0 py-1 bg-transparent outline-hidden placeholder:text-text-muted"
+            placeholder="Search"
+            value={pattern}
+            onChange={(e) => setPattern(e.target.value)}
+          />
+          <FlagButton
+            icon="CaseSensitive"
+            title="Match Case"
+            active={flags.caseSensitive}
+            onClick={() => toggle("caseSensitive")}
+          />
+          <FlagButton
+            icon="WholeWord"
+            title="Match Whole Word"
+            active={flags.wholeWord}
+            onClick={() => toggle("wholeWord")}
+          />
+          <FlagButton
+            icon="Regex"
+            title="Use Regular Expression"
+            active={flags.regex}
+            onClick={() => toggle("regex")}
+          />
+          <FlagButton
+            icon="Replace"
+            title="Replace"
+            active={replacing}
+            onClick={() => setReplacing((prev) => !prev)}
+          />
+        </div>
+        {replacing && (
+          <input
+            className="py-1 bg-transparent outline-hidden border-b border-surface placeholder:text-text-muted"
+            placeholder={flags.regex ? "Replace, e.g. $1" : "Replace"}
+            value={replacement}
+            onChange={(e) => setReplacement(e.target.value)}
+          />
+        )}
+        <input
+          className="py-1 bg-transparent outline-hidden border-b border-surface placeholder:text-text-muted"
+          placeholder="Files to include, e.g. *.md, journal/**"
+          value={include}
+          onChange={(e) => setInclude(e.target.value)}
+        />
+        <input
+          className="py-1 bg-transparent outline-hidden border-b border-surface placeholder:text-text-muted"
+          placeholder="Files to exclude"
+          value={exclude}
+          onChange={(e) => setExclude(e.target.value)}
+        />
+        {!replacing && status && (
+          <p className="text-mini text-text-muted">{status}</p>
+        )}
+      </div>
+      {replacing ? (
+        <ReplacePreview options={replaceOptions} />
+      ) : (
+        <ul className="flex-1 overflow-y-auto px-2 text-display">
+          {batches.map((batch) => (
+            <li key={batch.path} className="flex flex-col py-1">
+              <p className="px-2 truncate font-semibold">
+                {batch.path.replace(`${state.root}/`, "")}
+              </p>
+              {batch.matches.map((match) => (
+                <button
+                  key={match.line}
+                  className="flex gap-2 px-2 py-0.5 rounded text-left text-text-muted hover:bg-surface-muted hover:text-text"
+                  onClick={() =>
+                    navigate({
+                      pathname: "/editor",
+                      search: `?root=${state.root}&file=${batch.path}`,
+                    })
+                  }
+                >
+                  <span className="shrink-0 tabular-nums">{match.line}</span>
+                  <Line match={match} />
+                </button>
+              ))}
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+};
@@ -5,6 +5,7 @@ import type { IconName } from "@/components/icon";
 import { cn } from "@/utils/cn";
 import { useStore } from "@/components/store";
 import { Files } from "./files";
+import { Grep } from "./grep";
 
 type TabButtonProps = {
   "tooltip-title"?: string;
@@ -68,7 +69,6 @@ export const TabList = ({ defaultValue }: { defaultValue: string }) => {
           id="grep"
           value={value}
           handler={setValue}
-          disabled
         />
         <TabButton
           icon="Bookmark"
@@ -94,7 +94,9 @@ export const TabList = ({ defaultValue }: { defaultValue: string }) => {
           </div>
         </Content>
         <Content selected={value === "grep"}>
-          <div>Grep</div>
+          <div className="h-full">
+            <Grep />
+          </div>
         </Content>
         <Content selected={value === "saved"}>
           <div>Saved</div>
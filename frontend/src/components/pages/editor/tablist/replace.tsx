@@ -0,0 +1,199 @@
+import { useEffect, useRef, useState } from "react";
+import { toast } from "sonner";
+import { CancelError } from "@wailsio/runtime";
+
+import { useStore } from "@/components/store";
+import { fromError, toastifyError } from "@/utils/constants/status-codes";
+
+import type {
+  ReplaceLine,
+  ReplaceOptions,
+  ReplacePreview as Preview,
+  ReplaceRecord,
+} from "@go/noted/pkg/file";
+
+// Wait for a pause in typing before previewing.
+const PREVIEW_DELAY = 250;
+
+const key = (path: string, line: number) => `${path}:${line}`;
+
+// Ranges are in characters, which differ from string indices outside the BMP.
+const Change = ({ line }: { line: ReplaceLine }) => {
+  const chars = Array.from(line.before);
+  const parts: Array<{ text: string; match: boolean }> = [];
+  let cursor = 0;
+  for (const { start, end } of line.ranges) {
+    if (start > cursor)
+      parts.push({ text: chars.slice(cursor, start).join(""), match: false });
+    parts.push({ text: chars.slice(start, end).join(""), match: true });
+    cursor = end;
+  }
+  parts.push({ text: chars.slice(cursor).join(""), match: false });
+
+  return (
+    <span className="flex flex-col min-w-0">
+      <span className="truncate">
+        {parts.map((part, i) =>
+          part.match ? (
+            <del key={i} className="bg-surface-muted text-text rounded-sm">
+              {part.text}
+            </del>
+          ) : (
+            <span key={i}>{part.text.trimStart()}</span>
+          ),
+        )}
+      </span>
+      <span className="truncate text-text">{line.after.trimStart()}</span>
+    </span>
+  );
+};
+
+export const ReplacePreview = ({ options }: { options: ReplaceOptions }) => {
+  const { state, setState, services } = useStore();
+
+  const [preview, setPreview] = useState<Preview | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [excluded, setExcluded] = useState<Set<string>>(new Set());
+  const [applying, setApplying] = useState(false);
+  // Bumped after applying or undoing so the preview is made again.
+  const [generation, setGeneration] = useState(0);
+  // Undo runs from a toast, possibly long after this render.
+  const latest = useRef(state);
+  latest.current = state;
+
+  useEffect(() => {
+    setPreview(null);
+    setError(null);
+    setExcluded(new Set());
+    if (!options.search.pattern || !services.files) return;
+
+    const files = services.files;
+    let request: ReturnType<typeof files.previewReplace> | undefined;
+    const timeout = setTimeout(() => {
+      request = files.previewReplace(options);
+      request.then(setPreview).catch((error) => {
+        if (!(error instanceof CancelError))
+          setError(fromError(error)[0].message);
+      });
+    }, PREVIEW_DELAY);
+
+    return () => {
+      clearTimeout(timeout);
+      request?.cancel();
+    };
+  }, [options, services.files, generation]);
+
+  // The editor does not watch the disk, so reload the open note if it changed.
+  const reloadActiveTab = async (record: ReplaceRecord) => {
+    const { root, active_tab: tab } = latest.current;
+    if (!tab || !services.files || !record.files.includes(tab.path)) return;
+    if (tab.content !== tab.defaultContent) {
+      toast.warning(
+        `${tab.path.replace(`${root}/`, "")} has unsaved changes`,
+      );
+      return;
+    }
+    const content = await services.files.getFileContent(tab.path);
+    setState("active_tab", { ...tab, content, defaultContent: content });
+  };
+
+  const undo = (record: ReplaceRecord) =>
+    services.files
+      ?.undoReplace(record.id)
+      .then(async (record) => {
+        toast.success(
+          `Restored ${record.files.length} file${record.files.length === 1 ? "" : "s"}`,
+        );
+        setGeneration((n) => n + 1);
+        await reloadActiveTab(record);
+      })
+      .catch(toastifyError);
+
+  const selection = (preview?.files ?? [])
+    .map((file) => ({
+      path: file.path,
+      hash: file.hash,
+      lines: file.lines
+        .filter(({ line }) => !excluded.has(key(file.path, line)))
+        .map(({ line }) => line),
+    }))
+    // An empty selection would mean every line
+    .filter(({ lines }) => lines.length > 0);
+  const selected = selection.reduce((n, { lines }) => n + lines.length, 0);
+
+  const apply = async () => {
+    if (selection.length === 0 || !services.files) return;
+
+    setApplying(true);
+    try {
+      const record = await services.files.applyReplace(options, selection);
+      toast.success(
+        `Replaced ${record.lines} line${record.lines === 1 ? "" : "s"} in ${record.files.length} file${record.files.length === 1 ? "" : "s"}`,
+        { action: { label: "Undo", onClick: () => undo(record) } },
+      );
+      setGeneration((n) => n + 1);
+      await reloadActiveTab(record);
+    } catch (error) {
+      toastifyError(error);
+    } finally {
+      setApplying(false);
+    }
+  };
+
+  const toggle = (id: string) =>
+    setExcluded((prev) => {
+      const next = new Set(prev);
+      if (!next.delete(id)) next.add(id);
+      return next;
+    });
+
+  const status = (() => {
+    if (error) return error;
+    if (!options.search.pattern) return null;
+    if (!preview) return "Previewing...";
+    const found = `${preview.matches} match${preview.matches === 1 ? "" : "es"} in ${preview.files.length} file${preview.files.length === 1 ? "" : "s"}`;
+    return preview.truncated ? `${found} (stopped early)` : found;
+  })();
+
+  return (
+    <>
+      <div className="flex items-center justify-between gap-2 px-4 pb-2 text-display">
+        {status && <p className="text-mini text-text-muted">{status}</p>}
+        <button
+          className="px-2 py-0.5 rounded bg-surface-muted text-text disabled:opacity-50"
+          disabled={state.read_only || applying || selected === 0}
+          title={
+            state.read_only ? "This notespace is open read-only" : undefined
+          }
+          onClick={apply}
+        >
+          Replace {selected}
+        </button>
+      </div>
+      <ul className="flex-1 overflow-y-auto px-2 text-display">
+        {preview?.files.map((file) => (
+          <li key={file.path} className="flex flex-col py-1">
+            <p className="px-2 truncate font-semibold">
+              {file.path.replace(`${state.root}/`, "")}
+            </p>
+            {file.lines.map((line) => (
+              <label
+                key={line.line}
+                className="flex gap-2 px-2 py-0.5 rounded text-text-muted hover:bg-surface-muted"
+              >
+                <input
+                  type="checkbox"
+                  className="shrink-0"
+                  checked={!excluded.has(key(file.path, line.line))}
+                  onChange={() => toggle(key(file.path, line.line))}
+                />
+                <span className="shrink-0 tabular-nums">{line.line}</span>
+                <Change line={line} />
+              </label>
+            ))}
+          </li>
+        ))}
+      </ul>
+    </>
+  );
+};
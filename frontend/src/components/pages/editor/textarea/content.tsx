@@ -5,6 +5,7 @@ import { commands } from "@/command";
 import { useStore } from "@/components/store";
 import { type FileInfo, FileService } from "@/services";
 import { Editor } from "./editor";
+import { QueryBlocks } from "./queries";
 
 import LogoAltIcon from "~/images/logo-alt-icon.svg?react";
 import { getCommandShortcutNode } from "@/utils/command-helpers";
@@ -45,6 +46,7 @@ const EditorContent: FC<{
         onChange={setContent}
         style={{ overflow: "visible", paddingTop: "1.5rem", minHeight: "100%" }}
       />
+      {info.filetype === "markdown" && <QueryBlocks content={content} />}
     </div>
   );
 };
@@ -78,7 +80,7 @@ const Content = () => {
   });
 
   const handleSave = async (ev: React.KeyboardEvent<HTMLTextAreaElement>) => {
-    if (!state.active_tab) return;
+    if (!state.active_tab || state.read_only) return;
 
     const ta = ev.target as HTMLTextAreaElement;
     const { content, caret } = await services.files.format(
@@ -13,6 +13,8 @@ import {
 } from "@/components/ui/menubar";
 import { KeyIcon } from "@/components/icon";
 import { CommandPalette } from "@/components/dialogs/command-palette";
+import { SearchNotespace } from "@/components/dialogs/search-notespace";
+import { GoToFile } from "@/components/dialogs/go-to-file";
 import { Combobox } from "@/components/ui/combobox";
 import { Tablist } from "./tablist";
 import { commands } from "@/command";
@@ -27,7 +29,7 @@ export const Textarea = () => {
   );
 
   const handler = useCallback(async () => {
-    if (!state.active_tab) return;
+    if (!state.active_tab || state.read_only) return;
 
     await services.files.saveFileContent(
       state.active_tab.path,
@@ -38,7 +40,7 @@ export const Textarea = () => {
       ...state.active_tab,
       defaultContent: state.active_tab.content,
     });
-  }, [state.active_tab, services.files]);
+  }, [state.active_tab, state.read_only, services.files]);
 
   useEffect(() => {
     const cmd = commands.editorNotespaceFileSave.subscribe(handler);
@@ -102,6 +104,8 @@ export const Textarea = () => {
               tooltip-position="bottom"
             />
           </CommandPalette>
+          <SearchNotespace />
+          <GoToFile />
           <IconButton
             tooltip-title={`Chat with ${title}`}
             tooltip-position="bottom"
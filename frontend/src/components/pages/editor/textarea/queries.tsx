@@ -0,0 +1,152 @@
+import { FC, useEffect, useMemo, useState } from "react";
+import { useNavigate } from "react-router";
+import { CancelError } from "@wailsio/runtime";
+
+import { useStore } from "@/components/store";
+import { fromError } from "@/utils/constants/status-codes";
+
+import type { Result } from "@go/noted/pkg/query";
+
+// Wait for a pause in typing before running a changed query.
+const QUERY_DELAY = 400;
+
+// Fenced ```query blocks, and ```dataview so notes written for it keep working.
+const QUERY_BLOCK =
+  /^(`{3,}|~{3,})[ \t]*(?:query|dataview)[ \t]*\r?\n([\s\S]*?)^\1[ \t]*$/gm;
+
+const queryBlocks = (content: string) =>
+  Array.from(content.matchAll(QUERY_BLOCK), (match) => match[2].trim()).filter(
+    Boolean,
+  );
+
+const QueryTable: FC<{ query: string; generation: number }> = ({
+  query,
+  generation,
+}) => {
+  const { state, services } = useStore();
+  const navigate = useNavigate();
+
+  const [result, setResult] = useState<Result | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    const notespace = services.notespace;
+    let request: ReturnType<typeof notespace.runQuery> | undefined;
+    const timeout = setTimeout(() => {
+      request = notespace.runQuery(query);
+      request
+        .then((result) => {
+          setResult(result);
+          setError(null);
+        })
+        .catch((error) => {
+          if (error instanceof CancelError) return;
+          // The reason says what is wrong and where, e.g. "expected ) at 12"
+          const [{ message, reason }] = fromError(error);
+          setError(reason ? `${message}: ${reason}` : message);
+        });
+    }, QUERY_DELAY);
+
+    return () => {
+      clearTimeout(timeout);
+      request?.cancel();
+    };
+  }, [query, generation, services.notespace]);
+
+  if (error)
+    return (
+      <div className="rounded border border-surface-muted px-3 py-2 text-mini">
+        <p className="text-text">{error}</p>
+        <pre className="mt-1 whitespace-pre-wrap text-text-muted">{query}</pre>
+      </div>
+    );
+
+  if (!result) return null;
+
+  const rows = result.groups.reduce((n, group) => n + group.rows.length, 0);
+  const grouped = result.groups.some((group) => group.key !== "");
+
+  return (
+    <div className="rounded border border-surface-muted text-display">
+      <table className="w-full border-collapse text-left">
+        <thead className="text-text-muted">
+          <tr>
+            {result.columns.map((column, i) => (
+              <th key={i} className="px-3 py-1.5 font-semibold">
+                {column}
+              </th>
+            ))}
+          </tr>
+        </thead>
+        {result.groups.map((group) => (
+          <tbody key={group.key}>
+            {grouped && (
+              <tr>
+                <th
+                  colSpan={result.columns.length}
+                  className="px-3 pt-2 pb-1 font-semibold text-text"
+                >
+                  {group.key || "—"}
+                </th>
+              </tr>
+            )}
+            {group.rows.map((row) => (
+              <tr
+                key={row.path}
+                className="border-t border-surface-muted text-text-muted"
+              >
+                <td className="px-3 py-1">
+                  <button
+                    className="text-left text-text hover:underline"
+                    title={row.path.replace(`${state.root}/`, "")}
+                    onClick={() =>
+                      navigate({
+                        pathname: "/editor",
+                        search: `?root=${state.root}&file=${row.path}`,
+                      })
+                    }
+                  >
+                    {row.title}
+                  </button>
+                </td>
+                {row.cells.map((cell, i) => (
+                  <td key={i} className="px-3 py-1">
+                    {cell}
+                  </td>
+                ))}
+              </tr>
+            ))}
+          </tbody>
+        ))}
+      </table>
+      <p className="px-3 py-1.5 text-mini text-text-muted">
+        {rows === result.total
+          ? `${rows} note${rows === 1 ? "" : "s"}`
+          : `${rows} of ${result.total} notes`}
+      </p>
+    </div>
+  );
+};
+
+// Renders the query blocks of a note as live tables, run again whenever notes
+// change on disk.
+export const QueryBlocks: FC<{ content: string }> = ({ content }) => {
+  const { services } = useStore();
+  const queries = useMemo(() => queryBlocks(content), [content]);
+  const [generation, setGeneration] = useState(0);
+
+  useEffect(
+    () => services.notespace.onNotesChanged(() => setGeneration((n) => n + 1)),
+    [services.notespace],
+  );
+
+  if (queries.length === 0) return null;
+
+  return (
+    <div className="flex flex-col gap-4 px-4 pb-6">
+      {queries.map((query, i) => (
+        <QueryTable key={i} query={query} generation={generation} />
+      ))}
+    </div>
+  );
+};
@@ -2,9 +2,9 @@ import React, { useState } from "react";
 
 import { Button } from "@/components/ui/button";
 import { Loader } from "@/components/ui/loader";
-import { toast } from "sonner";
 
 import app from "@/utils/constants/app";
+import { toastifyError } from "@/utils/constants/status-codes";
 
 import { Editor } from "@go/noted/pkg/editor";
 
@@ -12,6 +12,7 @@ import LogoIcon from "~/images/logo-icon.svg?react";
 import { Icon } from "@/components/icon";
 import { Outlet } from "react-router";
 import { Action, RepoContext } from "./context";
+import { handleInspection } from "./inspection";
 
 const Repo = () => {
   const [action, setAction] = useState<Action | null>(null);
@@ -26,12 +27,12 @@ const Repo = () => {
     return async () => {
       try {
         setAction(type);
-        const dir = await handler();
-        console.log({ dir });
+        const inspection = await handler();
+        console.log({ inspection });
+        handleInspection(inspection);
       } catch (err) {
         console.log({ err });
-        const message = (err as Error).message || "An unknown error occurred";
-        toast.error(message);
+        toastifyError(err);
       } finally {
         setAction(null);
       }
@@ -0,0 +1,39 @@
+import { toast } from "sonner";
+import { Editor, type Inspection } from "@go/noted/pkg/editor";
+import { toastifyError } from "@/utils/constants/status-codes";
+
+const missingParts = (inspection: Inspection) => {
+	const missing: Array<string> = [];
+	if (!inspection.isGitRepo) missing.push("a git repository");
+	if (!inspection.hasConfig) missing.push("a notespace config");
+	return missing;
+};
+
+// Offers to initialise a folder that was inspected but not opened.
+export const handleInspection = (inspection: Inspection) => {
+	if (inspection.opened) return;
+
+	if (inspection.hasConfig && !inspection.configValid) {
+		toast.error("The notespace config file is invalid", {
+			description: inspection.configError,
+		});
+		return;
+	}
+
+	const description = [
+		`Missing ${missingParts(inspection).join(" and ")}.`,
+		inspection.parent && `It is inside the notespace at ${inspection.parent}.`,
+	]
+		.filter(Boolean)
+		.join(" ");
+
+	toast.warning(`${inspection.path} is not a notespace yet`, {
+		description,
+		action: {
+			label: "Initialise",
+			onClick: () => {
+				Editor.InitialiseNotespace(inspection.path).catch(toastifyError);
+			},
+		},
+	});
+};
@@ -1,9 +1,10 @@
 import { LoaderFunctionArgs, useLoaderData } from "react-router";
-import { toast } from "sonner";
 import { Button } from "@/components/ui/button";
 import { Loader } from "@/components/ui/loader";
 import { getServices, FileService } from "@/services";
+import { toastifyError } from "@/utils/constants/status-codes";
 import { useRepoContext } from "./context";
+import { handleInspection } from "./inspection";
 
 const Recents = () => {
   const { recents } = useLoaderData<LoaderData<typeof Recents.loader>>();
@@ -13,12 +14,12 @@ const Recents = () => {
   const openRecent = async (path: string) => {
     try {
       setAction("open");
-      const dir = await FileService.openRepoDirectory(path);
-      console.log({ dir });
+      const inspection = await FileService.openRepoDirectory(path);
+      console.log({ inspection });
+      handleInspection(inspection);
     } catch (err) {
       console.log({ err });
-      const message = (err as Error).message || "An unknown error occurred";
-      toast.error(message);
+      toastifyError(err);
     } finally {
       setAction(null);
     }
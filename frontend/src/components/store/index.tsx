@@ -16,6 +16,7 @@ const initialState: State = {
   dialog: null,
 
   root: "",
+  read_only: false,
   config: null,
   notespaces: [],
 
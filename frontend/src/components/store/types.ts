@@ -10,12 +10,14 @@ export type State = {
 
   dialog:
     | "cmd-palette"
+    | "go-to-file"
     | "search-notespace"
     | "settings"
     | "settings-notespace"
     | null;
 
   root: string;
+  read_only: boolean;
   config: Config | null;
   notespaces: Array<{ path: string; config: Config }>;
 
@@ -1,5 +1,13 @@
 import { Editor } from "@go/noted/pkg/editor";
 import { Scanner } from "@go/noted/pkg/file";
+import type {
+  GrepBatch,
+  GrepOptions,
+  ReplaceOptions,
+  ReplaceSelection,
+} from "@go/noted/pkg/file";
+import type { Edit } from "@go/noted/pkg/frontmatter";
+import { Events } from "@wailsio/runtime";
 
 import * as prettier from "prettier";
 import babel from "prettier/plugins/babel";
@@ -40,6 +48,49 @@ export class FileService {
     await Scanner.SaveFileData(path, content);
   }
 
+  // Works on unsaved content; only the edited keys change.
+  public async parseFrontmatter(content: string) {
+    return Scanner.ParseFrontmatter(content);
+  }
+
+  public async editFrontmatter(content: string, edits: Array<Edit>) {
+    return Scanner.EditFrontmatter(content, edits);
+  }
+
+  // Writes the note and returns its new content.
+  public async updateFrontmatter(path: string, edits: Array<Edit>) {
+    return Scanner.UpdateFrontmatter(path, edits);
+  }
+
+  // Matches arrive through onGrepMatches; cancel the returned promise to stop.
+  public grep(options: GrepOptions) {
+    return Scanner.Grep(this.root, options);
+  }
+
+  // Keep in sync with EVENT_GREP_MATCHES in pkg/file. Returns an unsubscribe function.
+  public onGrepMatches(id: string, callback: (batch: GrepBatch) => void) {
+    return Events.On("grep:matches", (event) => {
+      const batch = event.data as GrepBatch;
+      if (batch.id === id) callback(batch);
+    });
+  }
+
+  // Changes nothing; cancel the returned promise when the options change.
+  public previewReplace(options: ReplaceOptions) {
+    return Scanner.PreviewReplace(this.root, options);
+  }
+
+  public async applyReplace(
+    options: ReplaceOptions,
+    selection: Array<ReplaceSelection>,
+  ) {
+    return Scanner.ApplyReplace(this.root, options, selection);
+  }
+
+  public async undoReplace(id: string) {
+    return Scanner.UndoReplace(this.root, id);
+  }
+
   format(path: string, content: string): Promise<{ content: string }>;
   format(
     path: string,
@@ -88,8 +139,8 @@ export class FileService {
   }
 
   static async openRepoDirectory(root: string) {
-    const dir = await Editor.OpenRepoDirectory(root);
-    return dir;
+    const inspection = await Editor.OpenRepoDirectory(root);
+    return inspection;
   }
 
   static getFileInfo(root: string, path: string, ext?: string): FileInfo {
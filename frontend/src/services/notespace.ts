@@ -1,11 +1,30 @@
 import z from "zod";
-import type { Config } from "@go/noted/pkg/editor";
+import { Events } from "@wailsio/runtime";
+import type {
+  Config,
+  ConfigPatch,
+  FieldError,
+} from "@go/noted/pkg/editor";
 import { Editor } from "@go/noted/pkg/editor";
+import { Doctor } from "@go/noted/pkg/doctor";
+import { GraphOptions } from "@go/noted/pkg/links";
+import { Store } from "@go/noted/pkg/settings";
+import type { Patch } from "@go/noted/pkg/workspace";
 import { local } from "@/utils/localstorage";
 
+// Keep in sync with the EVENT_* constants in pkg/editor.
+export const NotespaceEvents = {
+  ConfigChanged: "notespace:config-changed",
+  ConfigInvalid: "notespace:config-invalid",
+  OpenFile: "notespace:open-file",
+  NotesChanged: "notespace:notes-changed",
+} as const;
+
 const notespacePathsSchema = z.array(z.string());
 
-type Notespace = { config: Config; path: string };
+type Notespace = { config: Config; path: string; pinned: boolean };
+// Payload of NotespaceEvents.ConfigInvalid (editor.ConfigError).
+type ConfigError = { path: string; errors: Array<FieldError> };
 
 export class NotespaceService {
   private root: string | undefined = undefined;
@@ -15,42 +34,37 @@ export class NotespaceService {
   }
 
   public async getRecentNotespaces(): Promise<Array<Notespace>> {
-    try {
-      let notespacePaths: Array<string> = [];
-      const unparsed = local.notespaces.get();
-
-      if (!unparsed) notespacePaths = this.root ? [this.root] : [];
-      else {
-        // Get JSON parsed notespaces including non-existing ones
-        const parsed = notespacePathsSchema.parse(unparsed);
-        // Filter the exisiting notespaces
-        notespacePaths = Array.from(
-          new Set(this.root ? [...parsed, this.root] : [...parsed]),
-        );
-      }
+    await this.importLocalRecents();
 
-      const notespaces = await Editor.GetNotespaceFromPaths(notespacePaths);
+    const notespaces = await Editor.GetRecentNotespaces();
+    return notespaces.filter(
+      (notespace): notespace is Notespace => notespace.config !== null,
+    );
+  }
 
-      const recents = notespaces.filter(
-        (notespace): notespace is Notespace => notespace.config !== null,
-      );
+  public async pinRecent(path: string, pinned: boolean) {
+    await Store.PinRecent(path, pinned);
+  }
 
-      // Update cache if there are non-existing notespaces
-      if (recents.length != notespacePaths.length)
-        local.notespaces.set(recents.map(({ path }) => path));
+  public async removeRecent(path: string) {
+    await Store.RemoveRecent(path);
+  }
 
-      return recents;
-    } catch (error) {
-      // Incase of error reset and return only current notespace (if valid)
-      const current = await this.getCurrentNotespace();
+  public async forgetMissingRecents() {
+    await Store.ForgetMissing();
+  }
 
-      if (current.config) {
-        local.notespaces.set(this.root ? [this.root] : []);
-        return [current] as Array<Notespace>;
-      }
+  // Recents used to live in localStorage; hand them to the settings store once.
+  private async importLocalRecents() {
+    try {
+      const unparsed = local.notespaces.get();
+      if (!unparsed) return;
 
+      const paths = notespacePathsSchema.parse(unparsed);
+      if (paths.length > 0) await Store.ImportRecents(paths);
       local.notespaces.set([]);
-      return [];
+    } catch (error) {
+      console.log({ error });
     }
   }
 
@@ -59,6 +73,137 @@ export class NotespaceService {
     return { config, path };
   }
 
+  public async getEditorState() {
+    return Editor.GetEditorState();
+  }
+
+  public async getOpenTabs() {
+    return Editor.GetOpenTabs();
+  }
+
+  // Kept in the workspace state so the notespace reopens with the same notes.
+  public async saveOpenTabs(paths: Array<string>, active: string | null) {
+    await Editor.SaveOpenTabs({ paths, active: active ?? "" });
+  }
+
+  public async getWorkspaceState() {
+    return Editor.GetWorkspaceState();
+  }
+
+  // Written to .noted/state.json by the backend shortly after the last update.
+  public async updateWorkspaceState(patch: Patch) {
+    return Editor.UpdateWorkspaceState(patch);
+  }
+
+  public async updateConfig(patch: ConfigPatch) {
+    return Editor.UpdateConfig(patch);
+  }
+
+  public async setRegistryEntry(key: string, value: string) {
+    return Editor.SetRegistryEntry(key, value);
+  }
+
+  public async removeRegistryEntry(key: string) {
+    return Editor.RemoveRegistryEntry(key);
+  }
+
+  // Cancel the returned promise when the query changes.
+  public search(query: string, limit = 50) {
+    return Editor.SearchNotespace(query, limit);
+  }
+
+  // Cancel the returned promise when the query changes.
+  public findFiles(query: string, limit = 50) {
+    return Editor.FindFiles(query, limit);
+  }
+
+  public async getBacklinks(path: string) {
+    return Editor.GetBacklinks(path);
+  }
+
+  // Links to missing files have an empty path.
+  public async getOutgoingLinks(path: string) {
+    return Editor.GetOutgoingLinks(path);
+  }
+
+  // Without options, returns the whole notespace up to the default limit.
+  public async getGraph(options: Partial<GraphOptions> = {}) {
+    return Editor.GetGraph(new GraphOptions(options));
+  }
+
+  // Nested tags are children of their parent, e.g. project/alpha of project.
+  public async getTags() {
+    return Editor.GetTags();
+  }
+
+  // Queries combine tags with AND, OR, NOT, -tag and parentheses.
+  public async findTagged(query: string) {
+    return Editor.FindTagged(query);
+  }
+
+  // Renames the tag and the tags nested below it in every note.
+  public async renameTag(from: string, to: string) {
+    return Editor.RenameTag(from, to);
+  }
+
+  // Cancel the returned promise when the query changes.
+  public runQuery(query: string) {
+    return Editor.RunQuery(query);
+  }
+
+  public async checkNotespace() {
+    if (!this.root) throw new Error("No notespace selected.");
+    return Doctor.Check(this.root);
+  }
+
+  // Only broken links, images and heading or block anchors.
+  public async checkLinks() {
+    if (!this.root) throw new Error("No notespace selected.");
+    return Doctor.CheckLinks(this.root);
+  }
+
+  // Applies the automatic fixes of the given issues, or of all fixable issues.
+  public async fixNotespace(ids: Array<string> = []) {
+    if (!this.root) throw new Error("No notespace selected.");
+    return Doctor.Fix(this.root, ids);
+  }
+
+  // Returns an unsubscribe function.
+  public onConfigChanged(callback: (notespace: Notespace) => void) {
+    return Events.On(NotespaceEvents.ConfigChanged, (event) => {
+      const notespace = event.data as Notespace;
+      if (notespace.path === this.root) callback(notespace);
+    });
+  }
+
+  // Returns an unsubscribe function.
+  public onConfigInvalid(callback: (error: ConfigError) => void) {
+    return Events.On(NotespaceEvents.ConfigInvalid, (event) => {
+      const error = event.data as ConfigError;
+      if (this.root && error.path.startsWith(this.root)) callback(error);
+    });
+  }
+
+  // Sent when the app is asked to open a note in this window, e.g. from the
+  // command line. Returns an unsubscribe function.
+  public onOpenFile(callback: (path: string) => void) {
+    return Events.On(NotespaceEvents.OpenFile, (event) => {
+      const path = event.data as string;
+      if (this.root && path.startsWith(this.root)) callback(path);
+    });
+  }
+
+  // Sent with the paths of notes that changed on disk, in batches. Returns an
+  // unsubscribe function.
+  public onNotesChanged(callback: (paths: Array<string>) => void) {
+    return Events.On(NotespaceEvents.NotesChanged, (event) => {
+      const paths = (event.data as Array<string>).filter(
+        (path) => this.root && path.startsWith(this.root),
+      );
+      if (paths.length > 0) callback(paths);
+    });
+  }
+
   public addRoot(root: string) {
     this.root = root;
   }
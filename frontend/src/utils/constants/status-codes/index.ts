@@ -39,3 +39,30 @@ export const toastify = (code: string) => {
 			break;
 	}
 };
+
+export type StatusError = {
+	code: string;
+	message: string;
+	path?: string;
+	reason?: string;
+	errors?: Array<StatusError>;
+};
+
+// Service methods reject with a CallError whose cause is the Go status.Error.
+export const fromError = (err: unknown): Array<StatusError> => {
+	const cause = (err as { cause?: unknown } | null)?.cause;
+	if (cause && typeof cause === "object" && "code" in cause) {
+		const error = cause as StatusError;
+		return error.errors?.length ? error.errors : [error];
+	}
+
+	const message = (err as Error | null)?.message || "An unknown error occurred";
+	return [{ code: "FAILED_UNKNOWN", message }];
+};
+
+export const toastifyError = (err: unknown) => {
+	for (const error of fromError(err)) {
+		if (status(error.code).message) toastify(error.code);
+		else toast.error(error.message);
+	}
+};
@@ -4,7 +4,9 @@ import (
 	"embed"
 	_ "embed"
 	"log"
+	"os"
 
+	"noted/pkg/cli"
 	"noted/pkg/notedapp"
 )
 
@@ -12,13 +14,23 @@ import (
 var assets embed.FS
 
 func main() {
+	// Subcommands work on notespaces from the terminal and never open a window
+	if cli.IsCommand(os.Args[1:]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	args, err := notedapp.ParseArgs(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	app := notedapp.New(
 		"Noted — by maw1a",
 		"Simple notes app based on Git. Write in markdown and web components. Save, share, collaborate across notes repositories. Because Notion sucks.",
 		assets,
 	)
 
-	err := app.Run()
+	err = app.Run(args)
 
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"noted/pkg/doctor"
+	"noted/pkg/editor"
+	"noted/pkg/file"
+	"noted/pkg/status"
+	"os"
+	"strings"
+)
+
+// command runs one subcommand with the arguments following its name and
+// returns the value to print as JSON.
+type command struct {
+	usage string
+	run   func(args []string) (any, error)
+}
+
+var commands = map[string]command{
+	"init": {
+		usage: "init [dir]              make dir (default: current) a notespace",
+		run:   runInit,
+	},
+	"info": {
+		usage: "info [dir]              describe the notespace state of dir",
+		run:   runInfo,
+	},
+	"tree": {
+		usage: "tree [-depth N] [-sort MODE] [dir]   list the files of a notespace",
+		run:   runTree,
+	},
+	"config": {
+		usage: "config get [-dir DIR] [key] | config set [-dir DIR] key value",
+		run:   runConfig,
+	},
+	"doctor": {
+		usage: "doctor [-fix] [-only ID,...] [dir]   check a notespace for problems",
+		run:   runDoctor,
+	},
+	"links": {
+		usage: "links [-fix] [dir]      report broken links, images and anchors",
+		run:   runLinks,
+	},
+}
+
+var order = []string{"init", "info", "tree", "config", "doctor", "links"}
+
+// errUsage is returned when a command is called with the wrong arguments.
+var errUsage = errors.New("usage")
+
+// errIssues fails a command whose result was printed but reports problems.
+var errIssues = errors.New("issues found")
+
+// IsCommand reports whether args (without the program name) ask for a subcommand
+// rather than for the app. A subcommand must come first. A folder of the same
+// name in the working directory is opened instead, so `noted tree` opens
+// ./tree if it exists; `noted -- NAME` always opens NAME.
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	if _, ok := commands[args[0]]; !ok {
+		return false
+	}
+	info, err := os.Stat(args[0])
+	return err != nil || !info.IsDir()
+}
+
+// Run executes the subcommand in args, printing its result as JSON to stdout
+// and failures as status errors to stderr. It returns the exit code.
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	// The editor package logs its progress, which is noise on the command line
+	log.SetOutput(io.Discard)
+
+	if len(args) == 0 || !IsCommand(args) {
+		printUsage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		printUsage(stdout)
+		return 0
+	}
+
+	result, err := cmd.run(args[1:])
+	if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(stderr, "usage: noted %s\n", cmd.usage)
+		return 2
+	}
+
+	if result != nil {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if jerr := encoder.Encode(result); jerr != nil {
+			fmt.Fprintln(stderr, jerr)
+			return 1
+		}
+	}
+	if errors.Is(err, errIssues) {
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n", status.MarshalError(err))
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: noted [-new-window] [-read-only] [path ...]")
+	for _, name := range order {
+		fmt.Fprintf(w, "       noted %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "A folder in the working directory named like a command is opened instead of")
+	fmt.Fprintln(w, "running it; paths after -- are never taken for a command.")
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+// dirArg returns the only positional argument, defaulting to the current directory.
+func dirArg(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return os.Getwd()
+	case 1:
+		return args[0], nil
+	}
+	return "", errUsage
+}
+
+// runInit prints the inspection even when some steps failed, so scripts can
+// see what was created.
+func runInit(args []string) (any, error) {
+	dir, err := dirArg(args)
+	if err != nil {
+		return nil, err
+	}
+	inspection, err := editor.Init(dir)
+	return inspection, err
+}
+
+func runInfo(args []string) (any, error) {
+	dir, err := dirArg(args)
+	if err != nil {
+		return nil, err
+	}
+	inspection, err := editor.Inspect(dir)
+	if err != nil {
+		return nil, err
+	}
+	return inspection, nil
+}
+
+func runTree(args []string) (any, error) {
+	flags := newFlagSet("tree")
+	depth := flags.Int("depth", 0, "")
+	sortMode := flags.String("sort", string(file.SortNatural), "")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	dir, err := dirArg(flags.Args())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := file.Tree(dir, file.SortMode(*sortMode), *depth)
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func runConfig(args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, errUsage
+	}
+
+	flags := newFlagSet("config " + args[0])
+	dir := flags.String("dir", ".", "")
+	if err := flags.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	rest := flags.Args()
+
+	switch args[0] {
+	case "get":
+		if len(rest) > 1 {
+			return nil, errUsage
+		}
+		key := ""
+		if len(rest) == 1 {
+			key = rest[0]
+		}
+		return editor.ConfigValue(*dir, key)
+	case "set":
+		if len(rest) != 2 {
+			return nil, errUsage
+		}
+		config, err := editor.SetConfigValue(*dir, rest[0], rest[1])
+		if err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+	return nil, errUsage
+}
+
+// runDoctor prints the report and fails while any issue remains.
+func runDoctor(args []string) (any, error) {
+	flags := newFlagSet("doctor")
+	fix := flags.Bool("fix", false, "")
+	only := flags.String("only", "", "")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	dir, err := dirArg(flags.Args())
+	if err != nil {
+		return nil, err
+	}
+
+	var report doctor.Report
+	if *fix {
+		var ids []string
+		if *only != "" {
+			ids = strings.Split(*only, ",")
+		}
+		report, err = doctor.Fix(dir, ids)
+	} else {
+		report, err = doctor.Diagnose(dir)
+	}
+	if err != nil {
+		return report, err
+	}
+	if len(report.Issues) > 0 {
+		return report, errIssues
+	}
+	return report, nil
+}
+
+// runLinks prints the link report and fails while any broken link remains.
+func runLinks(args []string) (any, error) {
+	flags := newFlagSet("links")
+	fix := flags.Bool("fix", false, "")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	dir, err := dirArg(flags.Args())
+	if err != nil {
+		return nil, err
+	}
+
+	var report doctor.Report
+	if *fix {
+		report, err = doctor.FixLinks(dir)
+	} else {
+		report, err = doctor.Links(dir)
+	}
+	if err != nil {
+		return report, err
+	}
+	if len(report.Issues) > 0 {
+		return report, errIssues
+	}
+	return report, nil
+}
@@ -0,0 +1,212 @@
+package doctor
+
+import (
+	"fmt"
+	"noted/pkg/file"
+	"noted/pkg/links"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// checkLinks reports links, wiki links and images whose target does not exist,
+// and links to headings or blocks that are not in the target note. When exactly
+// one file or heading is a likely match, the fix points the link at it.
+func (d *doctor) checkLinks() {
+	files := links.NewFiles(d.files...)
+	anchors := map[string]links.Anchors{}
+	anchorsOf := func(rel string) links.Anchors {
+		if a, ok := anchors[rel]; ok {
+			return a
+		}
+		data, _ := os.ReadFile(d.abs(rel))
+		anchors[rel] = links.ParseAnchors(string(data))
+		return anchors[rel]
+	}
+
+	byLooseName := map[string][]string{}
+	for _, rel := range d.files {
+		name := links.Loose(strings.TrimSuffix(path.Base(rel), path.Ext(rel)))
+		byLooseName[name] = append(byLooseName[name], rel)
+	}
+
+	for _, note := range d.notes {
+		data, err := os.ReadFile(d.abs(note))
+		if err != nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, l := range links.Parse(note, string(data)) {
+			key := fmt.Sprint(l.Line, l.Embed, l.Target)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			rel := files.Resolve(note, l)
+			if rel == "" {
+				if l.Kind == links.KindMarkdown {
+					// Links to folders are fine
+					if _, err := os.Stat(d.abs(l.Rel)); err == nil {
+						continue
+					}
+				}
+				d.add(d.brokenTarget(note, l, files, byLooseName))
+				continue
+			}
+			if (l.Heading != "" || l.Block != "") && isNote(rel) {
+				if issue, ok := d.brokenAnchor(note, l, rel, anchorsOf(rel)); ok {
+					d.add(issue)
+				}
+			}
+		}
+	}
+}
+
+// brokenTarget reports a link to a missing file, suggesting the one file with
+// the same name or, failing that, a name differing only in case or punctuation.
+func (d *doctor) brokenTarget(note string, l links.Link, files *links.Files, byLooseName map[string][]string) Issue {
+	missing := l.Rel
+	if l.Kind == links.KindWiki {
+		missing = links.WikiName(l.Target)
+	}
+	issue := Issue{
+		Check:    CheckBrokenLink,
+		Severity: SeverityWarning,
+		Path:     note,
+		Line:     l.Line,
+		Target:   l.Target,
+		Message:  fmt.Sprintf("Link to missing %s", missing),
+	}
+	if l.Embed && !isNote(missing) {
+		issue.Check = CheckBrokenImage
+		issue.Message = fmt.Sprintf("Image %s is missing", missing)
+	}
+
+	candidates := files.Named(path.Base(missing))
+	if len(candidates) != 1 {
+		candidates = byLooseName[links.Loose(strings.TrimSuffix(path.Base(missing), path.Ext(missing)))]
+	}
+	if len(candidates) != 1 {
+		return issue
+	}
+
+	var replacement string
+	if l.Kind == links.KindWiki {
+		replacement = wikiName(note, candidates[0], files) + strings.TrimPrefix(l.Target, links.WikiName(l.Target))
+	} else {
+		replacement = relativeTarget(note, candidates[0]) + suffix(l.Target)
+	}
+	issue.Fix = fmt.Sprintf("Point the link to %s", candidates[0])
+	issue.fix = d.replaceTarget(note, l.Line, l.Target, replacement)
+	return issue
+}
+
+// brokenAnchor reports a link to a heading or block that rel does not have.
+func (d *doctor) brokenAnchor(note string, l links.Link, rel string, anchors links.Anchors) (Issue, bool) {
+	issue := Issue{
+		Check:    CheckBrokenAnchor,
+		Severity: SeverityWarning,
+		Path:     note,
+		Line:     l.Line,
+		Target:   l.Target,
+	}
+	if l.Block != "" {
+		if anchors.HasBlock(l.Block) {
+			return issue, false
+		}
+		issue.Message = fmt.Sprintf("Block ^%s is not in %s", l.Block, rel)
+		return issue, true
+	}
+	if anchors.HasHeading(l.Heading) {
+		return issue, false
+	}
+	issue.Message = fmt.Sprintf("Heading \"%s\" is not in %s", l.Heading, rel)
+
+	heading, ok := anchors.SuggestHeading(l.Heading)
+	if !ok {
+		return issue, true
+	}
+	base, _, _ := strings.Cut(l.Target, "#")
+	replacement := base + "#" + heading
+	if l.Kind == links.KindMarkdown {
+		replacement = base + "#" + links.Slug(heading)
+	}
+	issue.Fix = fmt.Sprintf("Point the link to the heading \"%s\"", heading)
+	issue.fix = d.replaceTarget(note, l.Line, l.Target, replacement)
+	return issue, true
+}
+
+// wikiName returns the shortest wiki-link name that finds rel from note.
+func wikiName(note string, rel string, files *links.Files) string {
+	name := path.Base(rel)
+	if isNote(rel) {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	if files.Find(note, name) == rel {
+		return name
+	}
+	if isNote(rel) {
+		return strings.TrimSuffix(rel, path.Ext(rel))
+	}
+	return rel
+}
+
+// relativeTarget returns the link from note to rel.
+func relativeTarget(note string, rel string) string {
+	from := strings.Split(path.Dir(note), "/")
+	to := strings.Split(rel, "/")
+	if from[0] == "." {
+		from = nil
+	}
+
+	common := 0
+	for common < len(from) && common < len(to)-1 && from[common] == to[common] {
+		common++
+	}
+	parts := []string{}
+	for range from[common:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, to[common:]...)
+	return strings.ReplaceAll(strings.Join(parts, "/"), " ", "%20")
+}
+
+// suffix returns the fragment or query of target, kept when the link is fixed.
+func suffix(target string) string {
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		return target[i:]
+	}
+	return ""
+}
+
+// targetPattern matches links whose whole destination is target, so that a
+// fix for foo.md leaves a link to foo.md#intro on the same line alone.
+func targetPattern(target string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(target)
+	return regexp.MustCompile(`(\]\(\s*<)` + quoted + `(>)|(\]\(\s*)` + quoted + `([\s)])|(\[\[)` + quoted + `(\]\]|\|)`)
+}
+
+// replaceTarget returns a fix that swaps target for replacement in the links of
+// one line of note. Every link to target on the line is fixed, as the check
+// reports them once.
+func (d *doctor) replaceTarget(note string, line int, target string, replacement string) func() error {
+	return func() error {
+		path := d.abs(note)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		lines := strings.Split(string(data), "\n")
+		pattern := targetPattern(target)
+		if line > len(lines) || !pattern.MatchString(lines[line-1]) {
+			return fmt.Errorf("link to %s changed since the check", target)
+		}
+		// Groups that did not match expand to nothing
+		expand := "${1}${3}${5}" + strings.ReplaceAll(replacement, "$", "$$") + "${2}${4}${6}"
+		lines[line-1] = pattern.ReplaceAllString(lines[line-1], expand)
+		return file.WriteAtomic(path, []byte(strings.Join(lines, "\n")))
+	}
+}
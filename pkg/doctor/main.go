@@ -0,0 +1,431 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"noted/pkg/editor"
+	"noted/pkg/file"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MAX_FILE_SIZE is the size above which a note is reported as oversized.
+// The editor loads whole files into the webview.
+const MAX_FILE_SIZE = 10 << 20
+
+// sniffSize is how much of a file is looked at to tell text from binary.
+const sniffSize = 8000
+
+type Check string
+
+const (
+	CheckConfig        Check = "config"
+	CheckGit           Check = "git"
+	CheckBrokenLink    Check = "broken-link"
+	CheckBrokenImage   Check = "broken-image"
+	CheckBrokenAnchor  Check = "broken-anchor"
+	CheckUnreadable    Check = "unreadable"
+	CheckSymlink       Check = "symlink"
+	CheckConflict      Check = "conflict"
+	CheckOversized     Check = "oversized"
+	CheckCaseCollision Check = "case-collision"
+)
+
+// LINK_CHECKS make up the link report of Links.
+var LINK_CHECKS = []Check{CheckBrokenLink, CheckBrokenImage, CheckBrokenAnchor}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is one problem found in a notespace. IDs are stable between runs as
+// long as the problem stays where it is, so a report can be fixed selectively.
+type Issue struct {
+	ID       string   `json:"id"`
+	Check    Check    `json:"check"`
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+	// Target is the link or symlink target the issue is about.
+	Target string `json:"target,omitempty"`
+	// Fix describes the automatic fix; empty if the issue must be fixed by hand.
+	Fix string `json:"fix,omitempty"`
+
+	fix func() error
+}
+
+type Report struct {
+	Root   string  `json:"root"`
+	Files  int     `json:"files"`
+	Issues []Issue `json:"issues"`
+	// Fixed lists the issues fixed before the report was made.
+	Fixed []Issue `json:"fixed,omitempty"`
+}
+
+// Diagnose checks the notespace at root without changing anything.
+func Diagnose(root string) (Report, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return Report{Root: root}, status.New(status.FAILED_NOT_FOUND, root, err)
+	}
+	inspection, err := editor.Inspect(abs)
+	if err != nil {
+		return Report{Root: abs}, err
+	}
+
+	d := &doctor{root: abs, report: Report{Root: abs, Issues: []Issue{}}}
+	d.checkInspection(inspection)
+	if err := d.walk(); err != nil {
+		return d.report, status.FromOS(err, abs, status.FAILED_DOCTOR)
+	}
+	d.checkLinks()
+
+	slices.SortStableFunc(d.report.Issues, func(a, b Issue) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+	return d.report, nil
+}
+
+// Fix applies the automatic fixes of the issues in ids, or of every fixable
+// issue if ids is empty, and returns a fresh report.
+func Fix(root string, ids []string) (Report, error) {
+	report, err := Diagnose(root)
+	if err != nil {
+		return report, err
+	}
+
+	fixed := []Issue{}
+	errs := []*status.Error{}
+	for _, issue := range report.Issues {
+		if issue.fix == nil || (len(ids) > 0 && !slices.Contains(ids, issue.ID)) {
+			continue
+		}
+		if err := issue.fix(); err != nil {
+			log.Printf("Failed to fix %s: %v", issue.ID, err)
+			errs = append(errs, status.FromOS(err, issue.Path, status.FAILED_DOCTOR_FIX))
+			continue
+		}
+		fixed = append(fixed, issue)
+	}
+
+	after, err := Diagnose(root)
+	if err != nil {
+		return after, err
+	}
+	after.Fixed = fixed
+	return after, status.Join(errs...)
+}
+
+// Links reports only the broken links, images and heading or block anchors of
+// the notespace at root.
+func Links(root string) (Report, error) {
+	report, err := Diagnose(root)
+	return onlyLinks(report), err
+}
+
+// FixLinks applies every automatic fix of the link report.
+func FixLinks(root string) (Report, error) {
+	report, err := Links(root)
+	if err != nil {
+		return report, err
+	}
+
+	ids := []string{}
+	for _, issue := range report.Issues {
+		if issue.fix != nil {
+			ids = append(ids, issue.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return report, nil
+	}
+	report, err = Fix(root, ids)
+	return onlyLinks(report), err
+}
+
+func onlyLinks(report Report) Report {
+	report.Issues = slices.DeleteFunc(report.Issues, func(issue Issue) bool {
+		return !slices.Contains(LINK_CHECKS, issue.Check)
+	})
+	return report
+}
+
+type doctor struct {
+	root   string
+	report Report
+
+	// files holds every regular file, relative to root, for link resolution.
+	files []string
+	// notes are the markdown files whose links are checked.
+	notes []string
+	// names groups entries by their lower-cased path to find case-only collisions.
+	names map[string][]string
+}
+
+func (d *doctor) add(issue Issue) {
+	issue.ID = fmt.Sprintf("%s:%s", issue.Check, issue.Path)
+	if issue.Line > 0 {
+		issue.ID += fmt.Sprintf(":%d", issue.Line)
+	}
+	if issue.Target != "" {
+		issue.ID += ":" + issue.Target
+	}
+	d.report.Issues = append(d.report.Issues, issue)
+}
+
+func (d *doctor) checkInspection(inspection editor.Inspection) {
+	// Init only creates what is missing, so it is safe for both problems
+	initialise := func() error {
+		_, err := editor.Init(d.root)
+		return err
+	}
+
+	if !inspection.IsGitRepo {
+		d.add(Issue{
+			Check:    CheckGit,
+			Severity: SeverityWarning,
+			Path:     ".",
+			Message:  "The notespace is not a git repository",
+			Fix:      "Run git init",
+			fix:      initialise,
+		})
+	}
+
+	configPath := filepath.ToSlash(strings.TrimPrefix(editor.CONFIG_PATH, "/"))
+	switch {
+	case !inspection.HasConfig:
+		d.add(Issue{
+			Check:    CheckConfig,
+			Severity: SeverityError,
+			Path:     configPath,
+			Message:  "The notespace has no config file",
+			Fix:      "Create a default config file",
+			fix:      initialise,
+		})
+	case !inspection.ConfigValid && len(inspection.ConfigErrors) == 0:
+		d.add(Issue{
+			Check:    CheckConfig,
+			Severity: SeverityError,
+			Path:     configPath,
+			Message:  inspection.ConfigError,
+		})
+	case !inspection.ConfigValid:
+		for _, ferr := range inspection.ConfigErrors {
+			message := ferr.Message
+			if ferr.Field != "" {
+				message = ferr.Field + ": " + message
+			}
+			d.add(Issue{
+				Check:    CheckConfig,
+				Severity: SeverityError,
+				Path:     configPath,
+				Line:     ferr.Line,
+				Message:  message,
+			})
+		}
+	}
+}
+
+func (d *doctor) walk() error {
+	d.names = map[string][]string{}
+
+	err := filepath.WalkDir(d.root, func(path string, entry fs.DirEntry, err error) error {
+		rel := d.rel(path)
+		if err != nil {
+			if path == d.root {
+				return err
+			}
+			d.add(Issue{
+				Check:    CheckUnreadable,
+				Severity: SeverityError,
+				Path:     rel,
+				Message:  fmt.Sprintf("Cannot be read: %v", err),
+			})
+			return nil
+		}
+		if path == d.root {
+			return nil
+		}
+
+		key := strings.ToLower(rel)
+		d.names[key] = append(d.names[key], rel)
+
+		switch {
+		case entry.IsDir():
+			if slices.Contains(file.DefaultPruneDirNames, entry.Name()) {
+				return filepath.SkipDir
+			}
+		case entry.Type()&fs.ModeSymlink != 0:
+			d.checkSymlink(path, rel)
+		case entry.Type().IsRegular():
+			d.report.Files++
+			d.files = append(d.files, rel)
+			d.checkFile(path, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, paths := range d.names {
+		if len(paths) < 2 {
+			continue
+		}
+		slices.Sort(paths)
+		d.add(Issue{
+			Check:    CheckCaseCollision,
+			Severity: SeverityWarning,
+			Path:     paths[0],
+			Message:  fmt.Sprintf("Names differ only in case, which breaks on case-insensitive file systems: %s", strings.Join(paths, ", ")),
+		})
+	}
+	return nil
+}
+
+// checkSymlink reports links that cannot be resolved and links to a folder
+// containing them, which loop forever when followed.
+func (d *doctor) checkSymlink(path string, rel string) {
+	link, _ := os.Readlink(path)
+
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		d.add(Issue{
+			Check:    CheckSymlink,
+			Severity: SeverityWarning,
+			Path:     rel,
+			Target:   filepath.ToSlash(link),
+			Message:  "The symlink target is missing or loops",
+		})
+		return
+	}
+
+	info, err := os.Stat(target)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+	if within(parent, target) {
+		d.add(Issue{
+			Check:    CheckSymlink,
+			Severity: SeverityError,
+			Path:     rel,
+			Target:   filepath.ToSlash(link),
+			Message:  "The symlink points to a folder containing it, creating a cycle",
+		})
+	}
+}
+
+func (d *doctor) checkFile(path string, rel string) {
+	f, err := os.Open(path)
+	if err != nil {
+		d.add(Issue{
+			Check:    CheckUnreadable,
+			Severity: SeverityError,
+			Path:     rel,
+			Message:  fmt.Sprintf("Cannot be read: %v", err),
+		})
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err == nil && info.Size() > MAX_FILE_SIZE {
+		d.add(Issue{
+			Check:    CheckOversized,
+			Severity: SeverityWarning,
+			Path:     rel,
+			Message:  fmt.Sprintf("The file is %d MiB, larger than the %d MiB the editor handles well", info.Size()>>20, MAX_FILE_SIZE>>20),
+		})
+		return
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		d.add(Issue{
+			Check:    CheckUnreadable,
+			Severity: SeverityError,
+			Path:     rel,
+			Message:  fmt.Sprintf("Cannot be read: %v", err),
+		})
+		return
+	}
+	if bytes.IndexByte(data[:min(len(data), sniffSize)], 0) >= 0 {
+		return
+	}
+
+	d.checkConflicts(rel, data)
+	if isNote(rel) {
+		d.notes = append(d.notes, rel)
+	}
+}
+
+// checkConflicts reports every complete block of git merge conflict markers.
+func (d *doctor) checkConflicts(rel string, data []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, MAX_FILE_SIZE)
+
+	start, separated := 0, false
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "<<<<<<< ") || text == "<<<<<<<":
+			start, separated = line, false
+		case start > 0 && text == "=======":
+			separated = true
+		case start > 0 && separated && (strings.HasPrefix(text, ">>>>>>> ") || text == ">>>>>>>"):
+			d.add(Issue{
+				Check:    CheckConflict,
+				Severity: SeverityError,
+				Path:     rel,
+				Line:     start,
+				Message:  fmt.Sprintf("Unresolved merge conflict on lines %d-%d", start, line),
+			})
+			start, separated = 0, false
+		}
+	}
+}
+
+// rel returns path relative to the root with forward slashes.
+func (d *doctor) rel(path string) string {
+	rel, err := filepath.Rel(d.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (d *doctor) abs(rel string) string {
+	return filepath.Join(d.root, filepath.FromSlash(rel))
+}
+
+func isNote(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// within reports whether path is dir or inside it.
+func within(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
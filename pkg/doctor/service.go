@@ -0,0 +1,42 @@
+package doctor
+
+import (
+	"context"
+	"noted/pkg/file"
+	"noted/pkg/status"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// Doctor binds Diagnose and Fix for the editor windows.
+type Doctor struct {
+	guard file.WriteGuard
+}
+
+// Check reports the problems of the notespace at root.
+func (d *Doctor) Check(root string) (Report, error) {
+	return Diagnose(root)
+}
+
+// CheckLinks reports the broken links, images and anchors of the notespace at root.
+func (d *Doctor) CheckLinks(root string) (Report, error) {
+	return Links(root)
+}
+
+// Fix applies the automatic fixes of the issues in ids, or of all fixable
+// issues if ids is empty. Read-only windows may not fix anything.
+func (d *Doctor) Fix(ctx context.Context, root string, ids []string) (Report, error) {
+	if d.guard != nil {
+		if err := d.guard(ctx, root); err != nil {
+			return Report{Root: root}, err
+		}
+	}
+	return Fix(root, ids)
+}
+
+// Service binds the doctor. guard, if not nil, may refuse fixes requested by a window.
+func Service(guard file.WriteGuard) application.Service {
+	return application.NewServiceWithOptions(&Doctor{guard: guard}, application.ServiceOptions{
+		MarshalError: status.MarshalError,
+	})
+}
@@ -0,0 +1,345 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"noted/pkg/file"
+	"noted/pkg/status"
+	"noted/pkg/workspace"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CONFIG_VERSION is the schema version written by this build. Older configs are
+// migrated on open; newer ones are rejected rather than silently truncated.
+const CONFIG_VERSION = 1
+
+// FieldError points at a single problem in the config file.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// ConfigError lists everything wrong with a config file.
+type ConfigError struct {
+	Path   string       `json:"path"`
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ConfigError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, ferr := range e.Errors {
+		location := e.Path
+		if ferr.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", e.Path, ferr.Line, ferr.Column)
+		}
+		if ferr.Field != "" {
+			lines[i] = fmt.Sprintf("%s: %s: %s", location, ferr.Field, ferr.Message)
+		} else {
+			lines[i] = fmt.Sprintf("%s: %s", location, ferr.Message)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// migration upgrades a decoded config from version `from` to `from + 1`.
+type migration struct {
+	from  int
+	apply func(raw map[string]json.RawMessage) error
+}
+
+var migrations = []migration{
+	{
+		// Version 0: configs written before the schema was versioned.
+		from: 0,
+		apply: func(raw map[string]json.RawMessage) error {
+			if _, ok := raw["registry"]; !ok {
+				raw["registry"] = json.RawMessage("{}")
+			}
+			return nil
+		},
+	},
+}
+
+// scpRemote matches the scp-like remotes git accepts besides URLs, such as
+// git@github.com:owner/repo.git.
+var scpRemote = regexp.MustCompile(`^(?:[\w.~-]+@)?[\w.-]+:[^\\]+$`)
+
+var configFields = []string{"version", "name", "description", "repository", "homepage", "registry", "author"}
+
+func getConfig(path string) *Config {
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// loadConfig reads and validates the notespace config. Older versions are
+// migrated in memory only; use upgradeConfig to persist the migration.
+func loadConfig(path string) (*Config, error) {
+	configPath := filepath.Join(path, CONFIG_PATH)
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := parseConfig(configPath, data)
+	return config, err
+}
+
+// upgradeConfig migrates an older config on disk to CONFIG_VERSION, writing a
+// backup of the original first. Invalid configs are left untouched.
+func upgradeConfig(path string) error {
+	configPath := filepath.Join(path, CONFIG_PATH)
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return status.FromOS(err, configPath, status.FAILED_CONFIG_CHECK)
+	}
+
+	config, version, err := parseConfig(configPath, data)
+	if err != nil {
+		return status.New(status.FAILED_CONFIG_INVALID, configPath, err)
+	}
+	if version == CONFIG_VERSION {
+		return nil
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+	if err := file.WriteAtomic(backupPath, data); err != nil {
+		log.Printf("Failed to back up config before migration: %v", err)
+		return status.FromOS(err, backupPath, status.FAILED_CONFIG_MIGRATE)
+	}
+	if err := workspace.Ignore(path, filepath.Base(backupPath)); err != nil {
+		log.Printf("Failed to git-ignore config backup %s: %v", backupPath, err)
+	}
+	if err := writeConfig(path, config); err != nil {
+		return status.FromOS(err, configPath, status.FAILED_CONFIG_MIGRATE)
+	}
+
+	log.Printf("Migrated %s from version %d to %d (backup at %s)", configPath, version, CONFIG_VERSION, backupPath)
+	return nil
+}
+
+// writeConfig stores config with a stable key order (struct order, sorted registry keys).
+func writeConfig(path string, config *Config) error {
+	config.Version = CONFIG_VERSION
+	if config.Registry == nil {
+		config.Registry = map[string]string{}
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return file.WriteAtomic(filepath.Join(path, CONFIG_PATH), append(data, '\n'))
+}
+
+// parseConfig validates data and returns the migrated config together with the
+// version it was stored at.
+func parseConfig(configPath string, data []byte) (*Config, int, error) {
+	cerr := &ConfigError{Path: configPath}
+
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		ferr := FieldError{Message: err.Error()}
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			ferr.Line, ferr.Column = position(data, syntaxErr.Offset)
+		case errors.As(err, &typeErr):
+			ferr.Message = "config must be a JSON object"
+			ferr.Line, ferr.Column = position(data, typeErr.Offset)
+		}
+		cerr.Errors = append(cerr.Errors, ferr)
+		return nil, 0, cerr
+	}
+
+	version := 0
+	if value, ok := raw["version"]; ok {
+		if err := json.Unmarshal(value, &version); err != nil || version < 1 {
+			cerr.add(data, "version", "must be a positive integer")
+			return nil, 0, cerr
+		}
+	}
+	if version > CONFIG_VERSION {
+		cerr.add(data, "version", fmt.Sprintf("version %d is newer than this app supports (%d)", version, CONFIG_VERSION))
+		return nil, version, cerr
+	}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(raw); err != nil {
+			cerr.Errors = append(cerr.Errors, FieldError{Message: fmt.Sprintf("migration from version %d failed: %v", m.from, err)})
+			return nil, version, cerr
+		}
+	}
+	raw["version"] = json.RawMessage(fmt.Sprint(CONFIG_VERSION))
+
+	config := validateConfig(data, raw, cerr)
+	if len(cerr.Errors) > 0 {
+		slices.SortStableFunc(cerr.Errors, func(a, b FieldError) int {
+			return a.Line - b.Line
+		})
+		return nil, version, cerr
+	}
+	return config, version, nil
+}
+
+// validateConfig checks every field of raw and records problems in cerr.
+func validateConfig(data []byte, raw map[string]json.RawMessage, cerr *ConfigError) *Config {
+	config := &Config{Version: CONFIG_VERSION}
+
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		if !slices.Contains(configFields, key) {
+			cerr.add(data, key, "unknown field")
+		}
+	}
+
+	stringField := func(key string, target *string) bool {
+		value, ok := raw[key]
+		if !ok {
+			return false
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			cerr.add(data, key, "must be a string")
+			return false
+		}
+		return true
+	}
+	urlField := func(key string, target *string) {
+		if stringField(key, target) && *target != "" {
+			if u, err := url.Parse(*target); err != nil || u.Scheme == "" {
+				cerr.add(data, key, "must be an absolute URL")
+			}
+		}
+	}
+	remoteField := func(key string, target *string) {
+		if !stringField(key, target) || *target == "" || scpRemote.MatchString(*target) {
+			return
+		}
+		if u, err := url.Parse(*target); err != nil || u.Scheme == "" {
+			cerr.add(data, key, "must be a URL or a git remote")
+		}
+	}
+
+	if !stringField("name", &config.Name) {
+		if _, ok := raw["name"]; !ok {
+			cerr.add(data, "name", "is required")
+		}
+	} else if strings.TrimSpace(config.Name) == "" {
+		cerr.add(data, "name", "must not be empty")
+	}
+	stringField("description", &config.Description)
+	stringField("author", &config.Author)
+	remoteField("repository", &config.Repository)
+	urlField("homepage", &config.Homepage)
+
+	config.Registry = map[string]string{}
+	if value, ok := raw["registry"]; ok {
+		if err := json.Unmarshal(value, &config.Registry); err != nil {
+			cerr.add(data, "registry", "must be an object of strings")
+		}
+		for _, key := range slices.Sorted(maps.Keys(config.Registry)) {
+			if strings.TrimSpace(key) == "" {
+				cerr.add(data, "registry", "keys must not be empty")
+			} else if strings.TrimSpace(config.Registry[key]) == "" {
+				cerr.add(data, "registry."+key, "must not be empty")
+			}
+		}
+	}
+
+	return config
+}
+
+// add records a problem with field, locating the key in data when possible.
+func (e *ConfigError) add(data []byte, field string, message string) {
+	ferr := FieldError{Field: field, Message: message}
+	if offset, ok := keyOffsets(data)[field]; ok {
+		ferr.Line, ferr.Column = position(data, offset)
+	}
+	e.Errors = append(e.Errors, ferr)
+}
+
+// keyOffsets maps the keys of data to the offset of their opening quote. Keys
+// of nested objects are dotted ("registry.docs"); for repeated keys the last
+// one wins, as it does when decoding.
+func keyOffsets(data []byte) map[string]int64 {
+	offsets := map[string]int64{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(prefix string) error
+	walk = func(prefix string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		delim, ok := token.(json.Delim)
+		if !ok {
+			return nil
+		}
+		for decoder.More() {
+			if delim == '[' {
+				// Keys inside arrays are not config fields
+				if err := walk(prefix + "[]."); err != nil {
+					return err
+				}
+				continue
+			}
+			start := decoder.InputOffset()
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := prefix + token.(string)
+			offsets[key] = skipSeparators(data, start)
+			if err := walk(key + "."); err != nil {
+				return err
+			}
+		}
+		// The closing delimiter
+		_, err = decoder.Token()
+		return err
+	}
+	walk("")
+
+	return offsets
+}
+
+// skipSeparators returns the offset of the first byte at or after offset that
+// is not whitespace or a comma.
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// position converts a byte offset to a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,136 @@
+package editor
+
+import (
+	"fmt"
+	"log"
+	"noted/pkg/status"
+	"path/filepath"
+	"strings"
+)
+
+// The functions below work on notespaces without an Editor or any window,
+// for the command line.
+
+// Init creates whatever dir is missing to be a notespace (git repository,
+// config file). Problems that do not stop initialisation are returned as warnings
+// next to the inspection.
+func Init(dir string) (Inspection, error) {
+	if err := checkDirectory(dir); err != nil {
+		return Inspection{Path: dir}, err
+	}
+
+	_, errs := createNoteRepo(dir)
+	if err := upgradeConfig(dir); err != nil {
+		log.Printf("Failed to upgrade config of %s: %v", dir, err)
+	}
+
+	inspection, err := inspectNotespace(dir)
+	if err != nil {
+		return inspection, err
+	}
+	if inspection.HasConfig && !inspection.ConfigValid {
+		return inspection, status.New(status.FAILED_CONFIG_INVALID, filepath.Join(dir, CONFIG_PATH), nil)
+	}
+	return inspection, status.Join(errs...)
+}
+
+// Inspect reports the notespace state of dir without changing anything.
+func Inspect(dir string) (Inspection, error) {
+	return inspectNotespace(dir)
+}
+
+// ConfigValue returns key from the config of the notespace enclosing dir.
+// An empty key returns the whole config; "registry.<name>" returns one entry.
+func ConfigValue(dir string, key string) (any, error) {
+	root, err := notespaceRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	configPath := filepath.Join(root, CONFIG_PATH)
+
+	config, err := loadConfig(root)
+	if err != nil {
+		return nil, status.FromOS(err, configPath, status.FAILED_CONFIG_INVALID)
+	}
+
+	switch {
+	case key == "":
+		return config, nil
+	case key == "version":
+		return config.Version, nil
+	case key == "registry":
+		return config.Registry, nil
+	case strings.HasPrefix(key, "registry."):
+		value, ok := config.Registry[strings.TrimPrefix(key, "registry.")]
+		if !ok {
+			return nil, status.New(status.FAILED_CONFIG_KEY, configPath, fmt.Errorf("no registry entry %q", key))
+		}
+		return value, nil
+	}
+
+	field, ok := configField(config, key)
+	if !ok {
+		return nil, status.New(status.FAILED_CONFIG_KEY, configPath, fmt.Errorf("unknown key %q", key))
+	}
+	return *field, nil
+}
+
+// SetConfigValue sets key in the config of the notespace enclosing dir. Setting
+// an optional field to "" clears it. Running apps pick the change up from disk.
+func SetConfigValue(dir string, key string, value string) (Config, error) {
+	root, err := notespaceRoot(dir)
+	if err != nil {
+		return Config{}, err
+	}
+	configPath := filepath.Join(root, CONFIG_PATH)
+
+	config, err := rewriteConfig(root, func(config *Config) error {
+		if name, ok := strings.CutPrefix(key, "registry."); ok && name != "" {
+			config.Registry[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			return nil
+		}
+		field, ok := configField(config, key)
+		if !ok {
+			return status.New(status.FAILED_CONFIG_KEY, configPath, fmt.Errorf("cannot set %q", key))
+		}
+		*field = strings.TrimSpace(value)
+		return nil
+	})
+	if err != nil {
+		return Config{}, err
+	}
+	return *config, nil
+}
+
+// configField returns the string field of config named key.
+func configField(config *Config, key string) (*string, bool) {
+	switch key {
+	case "name":
+		return &config.Name, true
+	case "description":
+		return &config.Description, true
+	case "repository":
+		return &config.Repository, true
+	case "homepage":
+		return &config.Homepage, true
+	case "author":
+		return &config.Author, true
+	}
+	return nil, false
+}
+
+// notespaceRoot returns the notespace enclosing dir.
+func notespaceRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", status.New(status.FAILED_NOT_FOUND, dir, err)
+	}
+	if err := checkDirectory(abs); err != nil {
+		return "", err
+	}
+	root := findNotespace(abs)
+	if root == "" {
+		return "", status.New(status.FAILED_NO_NOTESPACE, abs, nil)
+	}
+	return root, nil
+}
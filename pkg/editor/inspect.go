@@ -0,0 +1,168 @@
+package editor
+
+import (
+	"errors"
+	"log"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+)
+
+// Inspection describes what a folder is missing before it can be used as a notespace.
+type Inspection struct {
+	Path        string `json:"path"`
+	IsGitRepo   bool   `json:"isGitRepo"`
+	HasConfig   bool   `json:"hasConfig"`
+	ConfigValid bool   `json:"configValid"`
+	ConfigError string `json:"configError,omitempty"`
+	// ConfigErrors locates each problem in the config file.
+	ConfigErrors []FieldError `json:"configErrors,omitempty"`
+	Config       *Config      `json:"config"`
+	// Parent is the root of an enclosing notespace when Path is nested inside one.
+	Parent string `json:"parent,omitempty"`
+	// Opened is true when an editor window was opened for Path.
+	Opened bool `json:"opened"`
+}
+
+// Ready reports whether the folder can be opened without initialising anything.
+func (i Inspection) Ready() bool {
+	return i.IsGitRepo && i.HasConfig && i.ConfigValid
+}
+
+// InspectDirectory reports the notespace state of dir without changing anything.
+func (e *Editor) InspectDirectory(dir string) (Inspection, error) {
+	return inspectNotespace(dir)
+}
+
+// InitialiseNotespace creates whatever dir is missing (git repository, config file)
+// and opens it in an editor window.
+func (e *Editor) InitialiseNotespace(dir string) (Inspection, error) {
+	if err := checkDirectory(dir); err != nil {
+		return Inspection{Path: dir}, err
+	}
+
+	_, errs := createNoteRepo(dir)
+	if err := upgradeConfig(dir); err != nil {
+		log.Printf("Failed to upgrade config of %s: %v", dir, err)
+	}
+	warnings := status.Join(errs...)
+
+	inspection, err := inspectNotespace(dir)
+	if err != nil {
+		return inspection, err
+	}
+	if inspection.HasConfig && !inspection.ConfigValid {
+		// createNoteRepo never overwrites an existing config, even a broken one
+		return inspection, status.New(status.FAILED_CONFIG_INVALID, filepath.Join(dir, CONFIG_PATH), nil)
+	}
+
+	return e.openInspected(inspection, warnings, nil)
+}
+
+// openNotespace inspects dir and opens it only if it is a complete notespace.
+// Otherwise the inspection is returned so the user can decide to initialise it.
+func (e *Editor) openNotespace(dir string) (Inspection, error) {
+	if err := upgradeConfig(dir); err != nil {
+		log.Printf("Failed to upgrade config of %s: %v", dir, err)
+	}
+
+	inspection, err := inspectNotespace(dir)
+	if err != nil {
+		return inspection, err
+	}
+	if !inspection.Ready() {
+		log.Printf("Not opening incomplete notespace %s: %+v", dir, inspection)
+		return inspection, nil
+	}
+
+	return e.openInspected(inspection, nil, nil)
+}
+
+// openInspected opens an editor window for a ready notespace. restore is the
+// saved state of the window when reopening the previous session; otherwise the
+// window continues where the notespace was left.
+func (e *Editor) openInspected(inspection Inspection, warnings error, restore *windowState) (Inspection, error) {
+	if restore == nil {
+		restore = e.lastState(inspection.Path)
+	}
+
+	window := createEditor(inspection.Path, restore)
+	e.openSession(inspection.Path, window, restore)
+	if err := showEditor(window, inspection.Path); err != nil {
+		e.closeSession(window.ID())
+		return inspection, err
+	}
+	inspection.Opened = true
+
+	if _, err := e.settings.AddRecent(inspection.Path); err != nil {
+		log.Printf("Failed to add %s to recents: %v", inspection.Path, err)
+	}
+
+	return inspection, warnings
+}
+
+// lastState is how the notespace at root was left, from its workspace state.
+func (e *Editor) lastState(root string) *windowState {
+	e.mu.Lock()
+	state := e.openWorkspace(root).Get()
+	e.mu.Unlock()
+
+	restore := &windowState{
+		tabs:      state.Tabs,
+		activeTab: state.ActiveTab,
+	}
+	if state.Window != nil {
+		restore.bounds = *state.Window
+	}
+	return restore
+}
+
+func inspectNotespace(dir string) (Inspection, error) {
+	inspection := Inspection{Path: dir}
+	if err := checkDirectory(dir); err != nil {
+		return inspection, err
+	}
+
+	inspection.IsGitRepo = isGitRepo(dir)
+
+	config, err := loadConfig(dir)
+	switch {
+	case err == nil:
+		inspection.HasConfig = true
+		inspection.ConfigValid = true
+		inspection.Config = config
+	case os.IsNotExist(err):
+	default:
+		inspection.HasConfig = true
+		inspection.ConfigError = err.Error()
+		var cerr *ConfigError
+		if errors.As(err, &cerr) {
+			inspection.ConfigErrors = cerr.Errors
+		}
+	}
+
+	inspection.Parent = findParentNotespace(dir)
+
+	return inspection, nil
+}
+
+// isGitRepo reports whether dir has a .git entry. Worktrees and submodules use a .git file.
+func isGitRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, GIT_PATH))
+	return err == nil
+}
+
+// findParentNotespace returns the closest ancestor of dir that has a notespace config.
+func findParentNotespace(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for parent := filepath.Dir(abs); parent != abs; abs, parent = parent, filepath.Dir(parent) {
+		if _, err := os.Stat(filepath.Join(parent, CONFIG_PATH)); err == nil {
+			return parent
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"context"
+	"noted/pkg/links"
+)
+
+// GetBacklinks returns the links to path from the notes of the calling window's
+// notespace, including [[wiki-links]] and embeds. The first call waits for the
+// links to be parsed.
+func (e *Editor) GetBacklinks(ctx context.Context, path string) ([]links.Link, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.links.Backlinks(ctx, path)
+}
+
+// GetOutgoingLinks returns the links of the note at path to files of the calling
+// window's notespace. Links to missing files have an empty path.
+func (e *Editor) GetOutgoingLinks(ctx context.Context, path string) ([]links.Link, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.links.Outgoing(ctx, path)
+}
+
+// GetGraph returns the notes of the calling window's notespace as nodes, with
+// their titles, tags and folders, and the links, embeds and shared tags between
+// them as edges. Note contents are not sent.
+func (e *Editor) GetGraph(ctx context.Context, opts links.GraphOptions) (links.Graph, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return links.Graph{}, err
+	}
+	return s.links.Graph(ctx, opts)
+}
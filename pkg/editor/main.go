@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"noted/pkg/links"
+	"noted/pkg/search"
+	"noted/pkg/settings"
+	"noted/pkg/status"
 	"noted/pkg/ui"
+	"noted/pkg/workspace"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 	"sync"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
@@ -19,6 +23,7 @@ const GIT_PATH = "/.git"
 const CONFIG_PATH = "/.noted/config.json"
 
 type Config struct {
+	Version     int               `json:"version"`
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
 	Repository  string            `json:"repository,omitempty"`
@@ -30,168 +35,154 @@ type Config struct {
 type Notespace struct {
 	Config *Config `json:"config"`
 	Path   string  `json:"path"`
+	Pinned bool    `json:"pinned"`
 }
 
 type Editor struct {
 	ctx context.Context
 
-	window *application.WebviewWindow
-
-	mu       sync.RWMutex
-	rootPath string
-	// rootTree   *Node
-	cancelScan context.CancelFunc
+	settings *settings.Store
+
+	// mu guards sessions and the state kept in each session
+	mu sync.RWMutex
+	// sessions maps an editor window ID to the notespace it shows
+	sessions map[uint]*session
+	// workspaces holds the state store of every open notespace, shared by its windows
+	workspaces map[string]*workspace.Store
+	// indexes holds the search index of every open notespace, shared by its windows
+	indexes map[string]*search.Index
+	// links holds the link index of every open notespace, shared by its windows
+	links map[string]*links.Index
+	// configMu serialises config writes across windows
+	configMu sync.Mutex
+	// quitting is set on shutdown so closing windows stay in the saved session
+	quitting bool
 }
 
 type EditorState struct {
-	rootPath string
+	Root     string `json:"root"`
+	ReadOnly bool   `json:"readOnly"`
 }
 
-func newEditor() *Editor {
+func newEditor(store *settings.Store) *Editor {
 	return &Editor{
-		ctx: context.Background(),
+		ctx:        context.Background(),
+		settings:   store,
+		sessions:   map[uint]*session{},
+		workspaces: map[string]*workspace.Store{},
+		indexes:    map[string]*search.Index{},
+		links:      map[string]*links.Index{},
 	}
 }
 
-func (e *Editor) CreateNewRepo() (string, error) {
+func (e *Editor) CreateNewRepo() (Inspection, error) {
 	// a. Select directory
 	dir, err := selectDirectory()
 
 	if err != nil {
-		return dir, err
+		return Inspection{Path: dir}, err
 	}
 
 	if dir == "" {
 		log.Print("User cancelled directory selection")
-		return dir, fmt.Errorf("User cancelled selection")
-	}
-
-	// b. Create Repo
-	success, errs := createNoteRepo(dir)
-	err = nil
-	if len(errs) > 0 {
-		err = errs[0]
-	}
-
-	if !success {
-		log.Printf("Failed to create notespace: %v", err)
-		return dir, fmt.Errorf("Failed to create notespace")
+		return Inspection{}, status.New(status.INFO_CANCELLED, "", nil)
 	}
 
-	// c. Open Editor
-	window := createEditor(dir)
-	e.window = window
-	e.rootPath = dir
-
-	return dir, err
+	// b. Create Repo and open Editor
+	return e.InitialiseNotespace(dir)
 }
 
-func (e *Editor) OpenExisitingRepo() (string, error) {
+func (e *Editor) OpenExisitingRepo() (Inspection, error) {
 	// a. Select directory
 	dir, err := selectDirectory()
 
 	if err != nil {
-		return dir, err
+		return Inspection{Path: dir}, err
 	}
 
 	if dir == "" {
 		log.Print("User cancelled directory selection")
-		return dir, fmt.Errorf("User cancelled selection")
+		return Inspection{}, status.New(status.INFO_CANCELLED, "", nil)
 	}
 
-	// b. Open Editor
-	window := createEditor(dir)
-	e.window = window
-	e.rootPath = dir
-
-	return dir, err
+	// b. Open Editor if the folder is a complete notespace
+	return e.openNotespace(dir)
 }
 
-func (e *Editor) OpenRepoDirectory(dir string) (string, error) {
-	success, err := directoryExists(dir)
+func (e *Editor) OpenRepoDirectory(dir string) (Inspection, error) {
+	return e.openNotespace(dir)
+}
 
+func (e *Editor) GetEditorState(ctx context.Context) EditorState {
+	s, err := e.sessionFor(ctx)
 	if err != nil {
-		log.Printf("Failed to check directory: %v", err)
-		return dir, fmt.Errorf("Failed to open")
-	}
-
-	if !success {
-		log.Printf("Not a directory: %v", err)
-		return dir, fmt.Errorf("Failed to open")
+		return EditorState{}
 	}
-
-	window := createEditor(dir)
-	e.window = window
-	e.rootPath = dir
-
-	return dir, err
-}
-
-func (e *Editor) GetEditorState() EditorState {
 	return EditorState{
-		rootPath: e.rootPath,
+		Root:     s.root,
+		ReadOnly: s.readOnly,
 	}
 }
 
-func (e *Editor) GetCurrentNotespace() Notespace {
-	config := getConfig(e.rootPath)
-	return Notespace{
-		Config: config,
-		Path:   e.rootPath,
+// GetCurrentNotespace returns the notespace shown in the calling window.
+func (e *Editor) GetCurrentNotespace(ctx context.Context) (Notespace, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return Notespace{}, err
 	}
+	return Notespace{
+		Config: getConfig(s.root),
+		Path:   s.root,
+	}, nil
 }
 
 func (e *Editor) GetNotespaceFromPaths(paths []string) []Notespace {
-	var results []Notespace
-	resultsChannel := make(chan Notespace)
+	results := make([]Notespace, len(paths))
 	var wg sync.WaitGroup
 
-	for _, path := range paths {
+	for i, path := range paths {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
 			config := getConfig(path)
-			resultsChannel <- Notespace{
+			results[i] = Notespace{
 				Config: config,
 				Path:   path,
 			}
 		}()
 	}
 
-	go func() {
-		wg.Wait()
-		close(resultsChannel)
-	}()
-
-	for result := range resultsChannel {
-		results = append(results, result)
-	}
+	wg.Wait()
 
 	return results
 }
 
-func directoryExists(path string) (bool, error) {
-	info, err := os.Stat(path)
-	if err == nil {
-		return info.IsDir(), nil
-	}
-	if os.IsNotExist(err) {
-		return false, nil
+// GetRecentNotespaces returns the recents kept by the settings store, pinned first.
+func (e *Editor) GetRecentNotespaces() []Notespace {
+	recents := e.settings.GetRecents()
+	paths := make([]string, len(recents))
+	for i, recent := range recents {
+		paths[i] = recent.Path
 	}
 
-	return false, err
+	notespaces := e.GetNotespaceFromPaths(paths)
+	for i := range notespaces {
+		notespaces[i].Pinned = recents[i].Pinned
+	}
+	return notespaces
 }
 
-func getConfig(path string) *Config {
-	configPath := filepath.Join(path, CONFIG_PATH)
-
-	if data, err := os.ReadFile(configPath); err == nil {
-		config := Config{}
-		if err := json.Unmarshal(data, &config); err == nil {
-			return &config
-		}
+// checkDirectory reports why path cannot be opened as a notespace, if it cannot.
+func checkDirectory(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return status.FromOS(err, path, status.FAILED_NOT_FOUND)
+	}
+	if !info.IsDir() {
+		return status.New(status.FAILED_NOT_DIRECTORY, path, nil)
 	}
+
 	return nil
 }
 
@@ -207,29 +198,29 @@ func selectDirectory() (string, error) {
 
 	dir, err := dialog.PromptForSingleSelection()
 	if err != nil {
-		return dir, fmt.Errorf("Failed to open dialog")
+		return dir, status.New(status.FAILED_DIALOG, "", err)
 	}
 
 	return dir, nil
 }
 
 // createNoteRepo handles the flow of creating a new notes repository
-func createNoteRepo(dir string) (bool, []error) {
+func createNoteRepo(dir string) (bool, []*status.Error) {
 	// Simple cross‑platform home directory
-	errors := []error{}
+	errors := []*status.Error{}
 
 	// Check if .git exists
-	gitDir := dir + GIT_PATH
-	if _, err := exec.Command("test", "-d", gitDir).Output(); err != nil {
+	if !isGitRepo(dir) {
 		// .git does not exist, run git init
 		if err := runGitInit(dir); err != nil {
 			log.Printf("Failed to initialize git repo: %v", err)
-			errors = append(errors, fmt.Errorf("Failed to initialize git repo"))
+			errors = append(errors, status.New(status.WARN_NO_GIT_INIT, dir, err))
 		}
 	}
 
-	name := strings.Split(dir, "/")[len(strings.Split(dir, "/"))-1]
+	name := filepath.Base(dir)
 	note := Config{
+		Version:  CONFIG_VERSION,
 		Name:     name,
 		Registry: map[string]string{},
 	}
@@ -242,7 +233,7 @@ func createNoteRepo(dir string) (bool, []error) {
 	} else if !os.IsNotExist(err) {
 		// Some other error
 		log.Printf("Failed to check Config file: %v", err)
-		errors = append(errors, fmt.Errorf("Failed to check config file"))
+		errors = append(errors, status.New(status.FAILED_CONFIG_CHECK, notePath, err))
 	} else {
 		log.Print("Creating Config file")
 
@@ -250,17 +241,17 @@ func createNoteRepo(dir string) (bool, []error) {
 
 		if err != nil {
 			log.Printf("Failed to create Config file: %v", err)
-			errors = append(errors, fmt.Errorf("Failed to create config file."))
+			errors = append(errors, status.New(status.WARN_NO_CONFIG_CREATE, notePath, err))
 		} else {
 			defer file.Close()
 			data, err := json.MarshalIndent(note, "", "  ")
 			if err != nil {
 				log.Printf("Failed to marshal Config file: %v", err)
-				errors = append(errors, fmt.Errorf("Failed to write config file."))
+				errors = append(errors, status.New(status.WARN_NO_CONFIG_WRITE, notePath, err))
 			} else {
 				if _, err := file.Write(data); err != nil {
 					log.Printf("Failed to write Config file: %v", err)
-					errors = append(errors, fmt.Errorf("Failed to write config file."))
+					errors = append(errors, status.New(status.WARN_NO_CONFIG_WRITE, notePath, err))
 				} else {
 					log.Print("Config file created successfully")
 				}
@@ -271,19 +262,38 @@ func createNoteRepo(dir string) (bool, []error) {
 	return true, errors
 }
 
-func createEditor(path string) *application.WebviewWindow {
+// createEditor creates the editor window for path without showing it, so a
+// session can be registered before the frontend starts calling in.
+func createEditor(path string, restore *windowState) *application.WebviewWindow {
 	app := application.Get()
 
-	window := ui.EditorWindow(app, path, getTitle(path))
+	window := ui.EditorWindow(app, path, getTitle(path), ui.EditorOptions{
+		File: restore.activeTab,
+		Bounds: application.Rect{
+			X:      restore.bounds.X,
+			Y:      restore.bounds.Y,
+			Width:  restore.bounds.Width,
+			Height: restore.bounds.Height,
+		},
+		Maximised: restore.bounds.Maximised,
+	})
+
+	if restore.bounds.IsZero() {
+		window.Center()
+	} else {
+		ui.ClampToScreen(window, restore.bounds.Screen)
+	}
 
-	window.Center()
+	return window
+}
 
-	if err := window.Show(); err != nil {
-		log.Printf("Failed to show editor window: %v", err)
-		return nil
+func showEditor(window *application.WebviewWindow, path string) error {
+	if window.Show() == nil {
+		log.Printf("Failed to show editor window for %s", path)
+		return status.New(status.FAILED_WINDOW, path, nil)
 	}
 
-	return window
+	return nil
 }
 
 func getTitle(path string) string {
@@ -296,12 +306,8 @@ func getTitle(path string) string {
 	}
 	title := rootDir
 
-	configPath := filepath.Join(path, CONFIG_PATH)
-	if data, err := os.ReadFile(configPath); err == nil {
-		var config Config
-		if err := json.Unmarshal(data, &config); err == nil && config.Name != "" {
-			title = config.Name
-		}
+	if config := getConfig(path); config != nil && config.Name != "" {
+		title = config.Name
 	}
 
 	return title
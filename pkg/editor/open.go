@@ -0,0 +1,141 @@
+package editor
+
+import (
+	"context"
+	"log"
+	"noted/pkg/file"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// EVENT_OPEN_FILE is sent to a window with the path of a note it should show.
+const EVENT_OPEN_FILE = "notespace:open-file"
+
+// OpenOptions control how a path given from outside the app is opened.
+type OpenOptions struct {
+	// NewWindow opens another window even if the notespace is already open.
+	NewWindow bool
+	// ReadOnly refuses every change made from the window. Opening it writes
+	// nothing either: the config is not migrated and the workspace state, tabs
+	// and bounds included, is kept in memory only.
+	ReadOnly bool
+}
+
+// Open opens path as a notespace, or the notespace enclosing path with that
+// note active. Unless opts.NewWindow is set, a window already showing the
+// notespace is focused instead, if it is read-only exactly when opts asks for
+// that; a read-only request never focuses a writable window.
+func Open(service application.Service, path string, opts OpenOptions) (Inspection, error) {
+	e, ok := service.Instance().(*Editor)
+	if !ok {
+		return Inspection{Path: path}, status.New(status.FAILED_UNKNOWN, path, nil)
+	}
+	return e.openPath(path, opts)
+}
+
+// WriteGuard refuses changes requested by read-only editor windows.
+func WriteGuard(service application.Service) file.WriteGuard {
+	e, ok := service.Instance().(*Editor)
+	if !ok {
+		return nil
+	}
+	return e.checkWrite
+}
+
+func (e *Editor) openPath(path string, opts OpenOptions) (Inspection, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Inspection{Path: path}, status.New(status.FAILED_NOT_FOUND, path, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return Inspection{Path: abs}, status.FromOS(err, abs, status.FAILED_NOT_FOUND)
+	}
+
+	root, note := abs, ""
+	if !info.IsDir() {
+		note = abs
+		root = findNotespace(filepath.Dir(abs))
+		if root == "" {
+			return Inspection{Path: abs}, status.New(status.FAILED_NO_NOTESPACE, abs, nil)
+		}
+	}
+
+	if !opts.NewWindow {
+		if s := e.firstSession(root, opts.ReadOnly); s != nil {
+			s.window.Focus()
+			if note != "" {
+				s.emit(EVENT_OPEN_FILE, note)
+			}
+			inspection, err := inspectNotespace(root)
+			// Focusing the existing window counts as opening it
+			inspection.Opened = err == nil
+			return inspection, err
+		}
+	}
+
+	// A read-only open validates the config as migrated in memory only
+	if !opts.ReadOnly {
+		if err := upgradeConfig(root); err != nil {
+			log.Printf("Failed to upgrade config of %s: %v", root, err)
+		}
+	}
+	inspection, err := inspectNotespace(root)
+	if err != nil {
+		return inspection, err
+	}
+	if !inspection.Ready() {
+		log.Printf("Not opening incomplete notespace %s: %+v", root, inspection)
+		return inspection, nil
+	}
+
+	restore := e.lastState(root)
+	restore.readOnly = opts.ReadOnly
+	if note != "" {
+		if !slices.Contains(restore.tabs, note) {
+			restore.tabs = append(restore.tabs, note)
+		}
+		restore.activeTab = note
+	}
+	return e.openInspected(inspection, nil, restore)
+}
+
+// checkWrite refuses changes to a notespace from a read-only window. Calls
+// from windows without a session, such as the picker, are allowed.
+func (e *Editor) checkWrite(ctx context.Context, path string) error {
+	s, err := e.sessionFor(ctx)
+	if err != nil || !s.readOnly {
+		return nil
+	}
+	return status.New(status.FAILED_READ_ONLY, path, nil)
+}
+
+// firstSession returns the earliest opened window showing root whose read-only
+// mode is readOnly, if any.
+func (e *Editor) firstSession(root string, readOnly bool) *session {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var first *session
+	for _, s := range e.sessionsFor(root) {
+		if s.readOnly != readOnly {
+			continue
+		}
+		if first == nil || s.window.ID() < first.window.ID() {
+			first = s
+		}
+	}
+	return first
+}
+
+// findNotespace returns dir or the closest ancestor of dir that has a notespace config.
+func findNotespace(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, CONFIG_PATH)); err == nil {
+		return dir
+	}
+	return findParentNotespace(dir)
+}
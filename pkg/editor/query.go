@@ -0,0 +1,26 @@
+package editor
+
+import (
+	"context"
+	"noted/pkg/query"
+	"time"
+)
+
+// RunQuery evaluates a TABLE or LIST query over the frontmatter, tags and file
+// details of the notes of the calling window's notespace. See query.Query for
+// the language.
+func (e *Editor) RunQuery(ctx context.Context, source string) (query.Result, error) {
+	q, err := query.Parse(source)
+	if err != nil {
+		return query.Result{}, err
+	}
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return query.Result{}, err
+	}
+	notes, err := s.links.Metadata(ctx)
+	if err != nil {
+		return query.Result{}, err
+	}
+	return query.Run(ctx, q, notes, time.Now())
+}
@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"errors"
+	"log"
+	"noted/pkg/file"
+	"noted/pkg/watch"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+)
+
+// EVENT_CONFIG_INVALID is emitted with a ConfigError when the config on disk stops validating.
+const EVENT_CONFIG_INVALID = "notespace:config-invalid"
+
+// EVENT_NOTES_CHANGED is emitted with the changed paths once the indexes have
+// caught up with changes on disk, so views built from them can refresh.
+const EVENT_NOTES_CHANGED = "notespace:notes-changed"
+
+// watchSession watches the notespace of s, keeping its indexes current and
+// reloading the config whenever it changes on disk. The watcher is closed
+// together with the session.
+func (e *Editor) watchSession(s *session) {
+	watcher, err := watch.New(s.root, file.DefaultPruneDirNames)
+	if err != nil {
+		log.Printf("Failed to watch notespace %s: %v", s.root, err)
+		return
+	}
+
+	configPath := filepath.Join(s.root, CONFIG_PATH)
+	watcher.Subscribe(func(events []watch.Event) {
+		for _, event := range events {
+			if event.Path == configPath {
+				e.reloadConfig(s)
+				break
+			}
+		}
+
+		// Saving the workspace state must not rerun every query of the window
+		notes := slices.DeleteFunc(slices.Clone(events), func(event watch.Event) bool {
+			return !indexed(s.root, event.Path)
+		})
+		if len(notes) == 0 {
+			return
+		}
+		s.index.Apply(notes)
+		s.links.Apply(notes)
+
+		paths := make([]string, len(notes))
+		for i, event := range notes {
+			paths[i] = event.Path
+		}
+		s.emit(EVENT_NOTES_CHANGED, paths)
+	})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if s.ctx.Err() != nil {
+		// The window closed while the watcher was starting
+		watcher.Close()
+		return
+	}
+	s.watcher = watcher
+}
+
+// reloadConfig revalidates the config of s and pushes it to its window if it changed.
+func (e *Editor) reloadConfig(s *session) {
+	config, err := loadConfig(s.root)
+	if err != nil {
+		log.Printf("Config of %s changed on disk and is no longer usable: %v", s.root, err)
+		var cerr *ConfigError
+		if !errors.As(err, &cerr) {
+			message := err.Error()
+			if os.IsNotExist(err) {
+				message = "config file was removed"
+			}
+			cerr = &ConfigError{
+				Path:   filepath.Join(s.root, CONFIG_PATH),
+				Errors: []FieldError{{Message: message}},
+			}
+		}
+		s.emit(EVENT_CONFIG_INVALID, cerr)
+		return
+	}
+
+	e.mu.Lock()
+	unchanged := reflect.DeepEqual(s.config, config)
+	s.config = config
+	e.mu.Unlock()
+
+	if !unchanged {
+		configChanged([]*session{s}, config)
+	}
+}
@@ -0,0 +1,171 @@
+package editor
+
+import (
+	"context"
+	"log"
+	"maps"
+	"noted/pkg/settings"
+	"noted/pkg/workspace"
+	"os"
+	"slices"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// Tabs are the notes open in an editor window.
+type Tabs struct {
+	Paths  []string `json:"paths"`
+	Active string   `json:"active"`
+}
+
+// Restore reopens the editor windows of the previous session unless the user
+// prefers to start fresh. It must be called before the app runs and reports
+// whether any window was opened.
+func Restore(service application.Service) bool {
+	e, ok := service.Instance().(*Editor)
+	if !ok {
+		return false
+	}
+	return e.restoreSession()
+}
+
+// GetOpenTabs returns the notes open in the calling window.
+func (e *Editor) GetOpenTabs(ctx context.Context) (Tabs, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return Tabs{Paths: []string{}}, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return Tabs{
+		Paths:  slices.Clone(s.tabs),
+		Active: s.activeTab,
+	}, nil
+}
+
+// SaveOpenTabs remembers the notes open in the calling window for the next
+// launch, in the notespace's workspace state.
+func (e *Editor) SaveOpenTabs(ctx context.Context, tabs Tabs) error {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	s.tabs = slices.Clone(tabs.Paths)
+	if s.tabs == nil {
+		s.tabs = []string{}
+	}
+	s.activeTab = tabs.Active
+	e.mu.Unlock()
+
+	s.workspace.Update(workspace.Patch{
+		Tabs:      &tabs.Paths,
+		ActiveTab: &tabs.Active,
+	})
+	return nil
+}
+
+func (e *Editor) ServiceShutdown() error {
+	e.mu.Lock()
+	e.quitting = true
+	empty := len(e.sessions) == 0
+	e.mu.Unlock()
+
+	// With no windows left the last one closed was already saved
+	if !empty {
+		e.saveSession()
+	}
+	e.flushWorkspaces()
+	return nil
+}
+
+func (e *Editor) restoreSession() bool {
+	if e.settings.GetSettings().Preferences.StartFresh {
+		return false
+	}
+
+	restored := false
+	for _, saved := range e.settings.GetSession() {
+		inspection, err := inspectNotespace(saved.Path)
+		if err != nil || !inspection.Ready() {
+			log.Printf("Not restoring notespace %s: %v", saved.Path, err)
+			continue
+		}
+
+		// The session only places the window; its tabs are the workspace's.
+		// Windows of one notespace therefore reopen with the same tabs.
+		restore := e.lastState(saved.Path)
+		if !saved.Bounds.IsZero() {
+			restore.bounds = saved.Bounds
+		}
+		restore.readOnly = saved.ReadOnly
+
+		// Notes deleted since the last launch would fail to load
+		restore.tabs = slices.DeleteFunc(restore.tabs, func(path string) bool {
+			_, err := os.Stat(path)
+			return err != nil
+		})
+		if !slices.Contains(restore.tabs, restore.activeTab) {
+			restore.activeTab = ""
+		}
+
+		if _, err := e.openInspected(inspection, nil, restore); err != nil {
+			log.Printf("Failed to restore notespace %s: %v", saved.Path, err)
+			continue
+		}
+		restored = true
+	}
+	return restored
+}
+
+// saveSession writes the open windows to the settings store, in the order they were opened.
+func (e *Editor) saveSession() {
+	e.mu.RLock()
+	ids := slices.Sorted(maps.Keys(e.sessions))
+	windows := make([]settings.SessionWindow, len(ids))
+	for i, id := range ids {
+		s := e.sessions[id]
+		windows[i] = settings.SessionWindow{
+			Path:     s.root,
+			Bounds:   s.bounds,
+			ReadOnly: s.readOnly,
+		}
+	}
+	e.mu.RUnlock()
+
+	if err := e.settings.SaveSession(windows); err != nil {
+		log.Printf("Failed to save session: %v", err)
+	}
+}
+
+// trackBounds keeps the last normal (not maximised) geometry of the window of s,
+// both for the session and as the notespace's default placement.
+func (e *Editor) trackBounds(s *session) {
+	update := func(*application.WindowEvent) {
+		maximised := s.window.IsMaximised()
+		rect := s.window.Bounds()
+		screen, err := s.window.GetScreen()
+
+		e.mu.Lock()
+		s.bounds.Maximised = maximised
+		if !maximised && rect.Width > 0 && rect.Height > 0 {
+			s.bounds.X, s.bounds.Y = rect.X, rect.Y
+			s.bounds.Width, s.bounds.Height = rect.Width, rect.Height
+		}
+		if err == nil && screen != nil {
+			s.bounds.Screen = screen.ID
+		}
+		bounds := s.bounds
+		e.mu.Unlock()
+
+		s.workspace.Update(workspace.Patch{Window: &bounds})
+	}
+
+	s.window.OnWindowEvent(events.Common.WindowDidMove, update)
+	s.window.OnWindowEvent(events.Common.WindowDidResize, update)
+	s.window.OnWindowEvent(events.Common.WindowMaximise, update)
+	s.window.OnWindowEvent(events.Common.WindowUnMaximise, update)
+}
@@ -0,0 +1,127 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"noted/pkg/file"
+	"noted/pkg/links"
+	"noted/pkg/search"
+	"noted/pkg/watch"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// SEARCH_LIMIT caps the results of a search when the caller gives no limit.
+const SEARCH_LIMIT = 50
+
+// SearchNotespace runs a full-text query over the notes of the calling window's
+// notespace. Words are matched by their stem; "quoted phrases", prefix* and
+// -excluded words are supported. The first search waits for the index to be built.
+func (e *Editor) SearchNotespace(ctx context.Context, query string, limit int) ([]search.Result, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = SEARCH_LIMIT
+	}
+	return s.index.Search(ctx, query, limit)
+}
+
+// FindFiles fuzzy-matches query against the paths and note titles of the calling
+// window's notespace. Recently opened notes rank higher; an empty query lists
+// them first.
+func (e *Editor) FindFiles(ctx context.Context, query string, limit int) ([]search.FileMatch, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.index.Find(ctx, query, s.workspace.Get().Recent, limit)
+}
+
+// SaveHook reindexes notes written through the file service right away,
+// before the watcher reports them. Links are reparsed too.
+func SaveHook(service application.Service) file.SaveHook {
+	e, ok := service.Instance().(*Editor)
+	if !ok {
+		return nil
+	}
+	return e.fileSaved
+}
+
+func (e *Editor) fileSaved(path string) {
+	e.mu.RLock()
+	indexes := []*search.Index{}
+	for root, index := range e.indexes {
+		if (path == root || strings.HasPrefix(path, root+string(filepath.Separator))) && indexed(root, path) {
+			indexes = append(indexes, index)
+		}
+	}
+	linkIndexes := []*links.Index{}
+	for root, index := range e.links {
+		if (path == root || strings.HasPrefix(path, root+string(filepath.Separator))) && indexed(root, path) {
+			linkIndexes = append(linkIndexes, index)
+		}
+	}
+	e.mu.RUnlock()
+
+	for _, index := range indexes {
+		index.Update(path)
+	}
+	for _, index := range linkIndexes {
+		index.Update(path)
+	}
+}
+
+// indexPrune are the folders the search and link indexes skip. The app's own
+// files are not notes, so the .noted folder is skipped too.
+var indexPrune = append(slices.Clone(file.DefaultPruneDirNames), file.APP_DIR)
+
+// indexed reports whether path is a file of root the indexes know. The app's
+// own files, in the .noted folder or ORDER_FILEs, are not.
+func indexed(root string, path string) bool {
+	return !watch.Pruned(root, path, indexPrune) && filepath.Base(path) != file.ORDER_FILE
+}
+
+// openIndexes returns the search and link indexes of root, building both from
+// one walk in the background on first use. The caller must hold e.mu.
+func (e *Editor) openIndexes(root string) (*search.Index, *links.Index) {
+	key := filepath.Clean(root)
+	if index, ok := e.indexes[key]; ok {
+		return index, e.links[key]
+	}
+
+	index := search.New(root, indexPrune)
+	linkIndex := links.New(root, indexPrune)
+	e.indexes[key] = index
+	e.links[key] = linkIndex
+	go buildIndexes(root, index, linkIndex)
+	return index, linkIndex
+}
+
+// buildIndexes fills the search and link indexes of root from a single walk
+// of the notespace.
+func buildIndexes(root string, index *search.Index, linkIndex *links.Index) {
+	start := time.Now()
+	err := watch.Walk(root, indexPrune, func(path string, info fs.FileInfo) error {
+		if !indexed(root, path) {
+			return nil
+		}
+		if err := index.Add(path, info); err != nil {
+			return err
+		}
+		return linkIndex.Add(path, info)
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("Failed to index %s: %v", root, err)
+	}
+
+	index.Finish(start)
+	linkIndex.Finish(start)
+}
@@ -1,7 +1,14 @@
 package editor
 
-import "github.com/wailsapp/wails/v3/pkg/application"
+import (
+	"noted/pkg/settings"
+	"noted/pkg/status"
 
-func Service() application.Service {
-	return application.NewService(newEditor())
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func Service(store *settings.Store) application.Service {
+	return application.NewServiceWithOptions(newEditor(store), application.ServiceOptions{
+		MarshalError: status.MarshalError,
+	})
 }
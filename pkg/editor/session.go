@@ -0,0 +1,171 @@
+package editor
+
+import (
+	"context"
+	"log"
+	"noted/pkg/links"
+	"noted/pkg/search"
+	"noted/pkg/settings"
+	"noted/pkg/status"
+	"noted/pkg/watch"
+	"noted/pkg/workspace"
+	"path/filepath"
+	"slices"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// session is the state of one editor window. Several windows may show the
+// same notespace; each keeps its own session.
+type session struct {
+	root      string
+	window    *application.WebviewWindow
+	config    *Config
+	watcher   *watch.Watcher
+	workspace *workspace.Store
+	index     *search.Index
+	links     *links.Index
+
+	// bounds are remembered in the session so the window can be restored on
+	// launch; tabs and activeTab in the workspace state
+	bounds    settings.Bounds
+	tabs      []string
+	activeTab string
+	readOnly  bool
+
+	// ctx is cancelled when the window closes, stopping any work started for it.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// windowState is what an editor window opens with.
+type windowState struct {
+	bounds    settings.Bounds
+	tabs      []string
+	activeTab string
+	readOnly  bool
+}
+
+// emit sends an event to this session's window only.
+func (s *session) emit(name string, data any) {
+	s.window.DispatchWailsEvent(&application.CustomEvent{
+		Name: name,
+		Data: data,
+	})
+}
+
+// openSession registers window as showing root and tears the session down when
+// the window closes. restore is the state the window was opened with.
+func (e *Editor) openSession(root string, window *application.WebviewWindow, restore *windowState) *session {
+	ctx, cancel := context.WithCancel(e.ctx)
+	s := &session{
+		root:   root,
+		window: window,
+		config: getConfig(root),
+		tabs:   []string{},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	// Bound calls may resolve the session as soon as it is registered
+	if restore != nil {
+		s.bounds = restore.bounds
+		s.tabs = slices.Clone(restore.tabs)
+		s.activeTab = restore.activeTab
+		s.readOnly = restore.readOnly
+	}
+
+	e.mu.Lock()
+	if s.readOnly {
+		// Read-only windows leave the notespace untouched, their state included
+		s.workspace = workspace.OpenReadOnly(root)
+	} else {
+		s.workspace = e.openWorkspace(root)
+	}
+	s.index, s.links = e.openIndexes(root)
+	e.sessions[window.ID()] = s
+	e.mu.Unlock()
+
+	id := window.ID()
+	window.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) {
+		e.closeSession(id)
+	})
+
+	e.watchSession(s)
+	e.trackBounds(s)
+	e.saveSession()
+	return s
+}
+
+// closeSession stops the work of a single window; other sessions are untouched.
+func (e *Editor) closeSession(id uint) {
+	e.mu.RLock()
+	s, ok := e.sessions[id]
+	// Closing the last window usually quits the app, so it is kept for the next launch
+	last := len(e.sessions) == 1
+	quitting := e.quitting
+	e.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+	if last && !quitting {
+		e.saveSession()
+	}
+
+	e.mu.Lock()
+	delete(e.sessions, id)
+	watcher := s.watcher
+	shared := len(e.sessionsFor(s.root)) > 0
+	if !shared {
+		delete(e.workspaces, filepath.Clean(s.root))
+		delete(e.indexes, filepath.Clean(s.root))
+		delete(e.links, filepath.Clean(s.root))
+	}
+	e.mu.Unlock()
+
+	s.cancel()
+	if watcher != nil {
+		watcher.Close()
+	}
+	if !shared {
+		s.index.Close()
+		s.links.Close()
+		if err := s.workspace.Flush(); err != nil {
+			log.Printf("Failed to write workspace state of %s: %v", s.root, err)
+		}
+	}
+
+	if !last && !quitting {
+		e.saveSession()
+	}
+}
+
+// sessionFor resolves the session of the window that made a bound call.
+func (e *Editor) sessionFor(ctx context.Context) (*session, error) {
+	window, ok := ctx.Value(application.WindowKey).(application.Window)
+	if !ok || window == nil {
+		return nil, status.New(status.FAILED_NO_SESSION, "", nil)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	s, ok := e.sessions[window.ID()]
+	if !ok {
+		return nil, status.New(status.FAILED_NO_SESSION, "", nil)
+	}
+	return s, nil
+}
+
+// sessionsFor returns every session showing root. The caller must hold e.mu.
+func (e *Editor) sessionsFor(root string) []*session {
+	root = filepath.Clean(root)
+	sessions := []*session{}
+	for _, s := range e.sessions {
+		if filepath.Clean(s.root) == root {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
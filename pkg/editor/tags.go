@@ -0,0 +1,109 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"noted/pkg/file"
+	"noted/pkg/status"
+	"noted/pkg/tags"
+	"os"
+	"slices"
+	"strings"
+)
+
+// TagRename is the outcome of renaming a tag.
+type TagRename struct {
+	// Files are the notes that were rewritten, sorted.
+	Files []string `json:"files"`
+	// Count is the number of tags replaced across them.
+	Count int `json:"count"`
+}
+
+// GetTags lists the tags of the calling window's notespace, from frontmatter
+// and inline #hashtags, nested by their slashes and with the number of notes
+// carrying each.
+func (e *Editor) GetTags(ctx context.Context) ([]tags.Tag, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.links.NoteTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tags.Tree(notes), nil
+}
+
+// FindTagged returns the notes of the calling window's notespace matching a tag
+// query such as "#project AND (draft OR review) NOT archived".
+func (e *Editor) FindTagged(ctx context.Context, query string) ([]tags.Note, error) {
+	q, err := tags.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.links.NoteTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tags.Select(notes, q), nil
+}
+
+// RenameTag replaces the tag from, and the tags nested below it, with to in
+// every note of the calling window's notespace. When a note cannot be written,
+// the notes rewritten so far are put back.
+func (e *Editor) RenameTag(ctx context.Context, from string, to string) (TagRename, error) {
+	from, to = strings.TrimPrefix(from, "#"), strings.TrimPrefix(to, "#")
+	if !tags.Valid(from) {
+		return TagRename{}, status.New(status.FAILED_TAG_RENAME, from, errors.New("not a valid tag"))
+	}
+	if !tags.Valid(to) {
+		return TagRename{}, status.New(status.FAILED_TAG_RENAME, to, errors.New("not a valid tag"))
+	}
+
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return TagRename{}, err
+	}
+	notes, err := s.links.NoteTags(ctx)
+	if err != nil {
+		return TagRename{}, err
+	}
+
+	result := TagRename{Files: []string{}}
+	renamed := map[string][]byte{}
+	for path, noteTags := range notes {
+		if !slices.ContainsFunc(noteTags, func(tag string) bool { return tags.Match(tag, from) }) {
+			continue
+		}
+		if err := e.checkWrite(ctx, path); err != nil {
+			return TagRename{}, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return TagRename{}, status.FromOS(err, path, status.FAILED_FILE_READ)
+		}
+		content, count := tags.Rename(string(data), from, to)
+		if count == 0 {
+			continue
+		}
+		renamed[path] = []byte(content)
+		result.Files = append(result.Files, path)
+		result.Count += count
+	}
+	slices.Sort(result.Files)
+	if err := ctx.Err(); err != nil {
+		return TagRename{}, err
+	}
+
+	if err := file.WriteAllAtomic(result.Files, func(path string) []byte { return renamed[path] }); err != nil {
+		return TagRename{}, err
+	}
+	for _, path := range result.Files {
+		e.fileSaved(path)
+	}
+	return result, nil
+}
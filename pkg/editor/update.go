@@ -0,0 +1,134 @@
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"noted/pkg/status"
+	"path/filepath"
+	"strings"
+)
+
+// EVENT_CONFIG_CHANGED is emitted with the updated Notespace whenever a config is written.
+const EVENT_CONFIG_CHANGED = "notespace:config-changed"
+
+// ConfigPatch holds the fields to change. Nil fields are left as they are;
+// an empty string clears an optional field.
+type ConfigPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Repository  *string `json:"repository,omitempty"`
+	Homepage    *string `json:"homepage,omitempty"`
+	Author      *string `json:"author,omitempty"`
+}
+
+// UpdateConfig applies patch to the current notespace's config.
+func (e *Editor) UpdateConfig(ctx context.Context, patch ConfigPatch) (Config, error) {
+	set := func(target *string, value *string) {
+		if value != nil {
+			*target = strings.TrimSpace(*value)
+		}
+	}
+
+	return e.modifyConfig(ctx, func(config *Config) {
+		set(&config.Name, patch.Name)
+		set(&config.Description, patch.Description)
+		set(&config.Repository, patch.Repository)
+		set(&config.Homepage, patch.Homepage)
+		set(&config.Author, patch.Author)
+	})
+}
+
+// SetRegistryEntry adds or replaces a registry entry of the current notespace.
+func (e *Editor) SetRegistryEntry(ctx context.Context, key string, value string) (Config, error) {
+	return e.modifyConfig(ctx, func(config *Config) {
+		config.Registry[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	})
+}
+
+// RemoveRegistryEntry deletes a registry entry of the current notespace.
+func (e *Editor) RemoveRegistryEntry(ctx context.Context, key string) (Config, error) {
+	return e.modifyConfig(ctx, func(config *Config) {
+		delete(config.Registry, key)
+	})
+}
+
+// modifyConfig performs a locked read-modify-write of the calling window's config.
+// The result is validated with the same rules used when opening a notespace.
+func (e *Editor) modifyConfig(ctx context.Context, modify func(config *Config)) (Config, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return Config{}, err
+	}
+	if s.readOnly {
+		return Config{}, status.New(status.FAILED_READ_ONLY, filepath.Join(s.root, CONFIG_PATH), nil)
+	}
+
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+
+	root := s.root
+	config, err := rewriteConfig(root, func(config *Config) error {
+		modify(config)
+		return nil
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Every window showing this notespace picks up the change, not just the caller
+	e.mu.Lock()
+	sessions := e.sessionsFor(root)
+	for _, other := range sessions {
+		other.config = config
+	}
+	e.mu.Unlock()
+
+	configChanged(sessions, config)
+	return *config, nil
+}
+
+// rewriteConfig loads the config of root, applies modify and writes the result
+// if it still validates.
+func rewriteConfig(root string, modify func(config *Config) error) (*Config, error) {
+	configPath := filepath.Join(root, CONFIG_PATH)
+
+	config, err := loadConfig(root)
+	if err != nil {
+		var cerr *ConfigError
+		if errors.As(err, &cerr) {
+			return nil, status.New(status.FAILED_CONFIG_INVALID, configPath, err)
+		}
+		return nil, status.FromOS(err, configPath, status.FAILED_CONFIG_CHECK)
+	}
+
+	if err := modify(config); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, status.New(status.FAILED_CONFIG_WRITE, configPath, err)
+	}
+	if _, _, err := parseConfig(configPath, data); err != nil {
+		return nil, status.New(status.FAILED_CONFIG_INVALID, configPath, err)
+	}
+
+	if err := writeConfig(root, config); err != nil {
+		log.Printf("Failed to write config: %v", err)
+		return nil, status.FromOS(err, configPath, status.FAILED_CONFIG_WRITE)
+	}
+	return config, nil
+}
+
+// configChanged retitles the windows of sessions and notifies their frontends.
+func configChanged(sessions []*session, config *Config) {
+	for _, s := range sessions {
+		s.window.SetTitle(getTitle(s.root))
+		s.emit(EVENT_CONFIG_CHANGED, Notespace{
+			Config: config,
+			Path:   s.root,
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package editor
+
+import (
+	"context"
+	"log"
+	"noted/pkg/workspace"
+	"path/filepath"
+	"slices"
+)
+
+// GetWorkspaceState returns the remembered tabs, bookmarks and cursors of the
+// calling window's notespace.
+func (e *Editor) GetWorkspaceState(ctx context.Context) (workspace.State, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return workspace.State{}, err
+	}
+	return s.workspace.Get(), nil
+}
+
+// UpdateWorkspaceState applies patch to the calling window's notespace state.
+// The file is written shortly after the last update.
+func (e *Editor) UpdateWorkspaceState(ctx context.Context, patch workspace.Patch) (workspace.State, error) {
+	s, err := e.sessionFor(ctx)
+	if err != nil {
+		return workspace.State{}, err
+	}
+
+	state := s.workspace.Update(patch)
+	if patch.Tabs != nil || patch.ActiveTab != nil {
+		e.mu.Lock()
+		s.tabs = slices.Clone(state.Tabs)
+		s.activeTab = state.ActiveTab
+		e.mu.Unlock()
+	}
+	return state, nil
+}
+
+// openWorkspace returns the state store of root, loading it on first use.
+// The caller must hold e.mu.
+func (e *Editor) openWorkspace(root string) *workspace.Store {
+	key := filepath.Clean(root)
+	if store, ok := e.workspaces[key]; ok {
+		return store
+	}
+
+	store := workspace.Open(root)
+	e.workspaces[key] = store
+	return store
+}
+
+// flushWorkspaces writes every pending workspace state, e.g. before quitting.
+func (e *Editor) flushWorkspaces() {
+	e.mu.RLock()
+	stores := make([]*workspace.Store, 0, len(e.workspaces))
+	for _, store := range e.workspaces {
+		stores = append(stores, store)
+	}
+	e.mu.RUnlock()
+
+	for _, store := range stores {
+		if err := store.Flush(); err != nil {
+			log.Printf("Failed to write workspace state of %s: %v", store.Root(), err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package file
+
+import (
+	"log"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic replaces path with data so that readers never see a partial file.
+// An existing file keeps its permissions; a new one is readable by everyone, as
+// with os.WriteFile and 0644.
+func WriteAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// CreateTemp makes the file 0600
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// WriteAllAtomic writes the content returned by content to every path, in order,
+// as one change. When a write fails, the files written so far are put back the
+// way they were.
+func WriteAllAtomic(paths []string, content func(path string) []byte) error {
+	previous := map[string][]byte{}
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = WriteAtomic(path, content(path))
+		}
+		if err != nil {
+			log.Printf("failed to write \"%s\", rolling back: %v", path, err)
+			for _, written := range paths[:i] {
+				if err := WriteAtomic(written, previous[written]); err != nil {
+					log.Printf("failed to roll back \"%s\": %v", written, err)
+				}
+			}
+			return status.FromOS(err, path, status.FAILED_FILE_WRITE)
+		}
+		previous[path] = data
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build darwin
+
+package file
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func createdTime(fi os.FileInfo) time.Time {
+	if stat, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Birthtimespec.Unix())
+	}
+	return fi.ModTime()
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !windows
+
+package file
+
+import (
+	"os"
+	"time"
+)
+
+// createdTime falls back to the modification time where the platform's
+// os.FileInfo does not carry a birth time.
+func createdTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package file
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func createdTime(fi os.FileInfo) time.Time {
+	if data, ok := fi.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.CreationTime.Nanoseconds())
+	}
+	return fi.ModTime()
+}
@@ -0,0 +1,55 @@
+package file
+
+import (
+	"context"
+	"log"
+	"noted/pkg/frontmatter"
+	"noted/pkg/status"
+	"os"
+)
+
+// ParseFrontmatter reads the YAML (---) or TOML (+++) frontmatter of a note's
+// content, such as an unsaved buffer.
+func (s *Scanner) ParseFrontmatter(content string) (frontmatter.Frontmatter, error) {
+	return frontmatter.Parse(content)
+}
+
+// EditFrontmatter sets and removes top-level keys in content and returns the
+// new content. The rest of the frontmatter and the body are left untouched.
+func (s *Scanner) EditFrontmatter(content string, edits []frontmatter.Edit) (string, error) {
+	return frontmatter.Apply(content, edits)
+}
+
+// GetFrontmatter reads the frontmatter of the note at path.
+func (s *Scanner) GetFrontmatter(path string) (frontmatter.Frontmatter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return frontmatter.Frontmatter{}, status.FromOS(err, path, status.FAILED_FILE_READ)
+	}
+	return frontmatter.Parse(string(data))
+}
+
+// UpdateFrontmatter edits the frontmatter of the note at path on disk and
+// returns its new content.
+func (s *Scanner) UpdateFrontmatter(ctx context.Context, path string, edits []frontmatter.Edit) (string, error) {
+	if err := s.checkWrite(ctx, path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", status.FromOS(err, path, status.FAILED_FILE_READ)
+	}
+	content, err := frontmatter.Apply(string(data), edits)
+	if err != nil {
+		return "", err
+	}
+	if content == string(data) {
+		return content, nil
+	}
+	if err := WriteAtomic(path, []byte(content)); err != nil {
+		log.Printf("failed to save file \"%s\" error: %v", path, err)
+		return "", status.FromOS(err, path, status.FAILED_FILE_WRITE)
+	}
+	s.saved(path)
+	return content, nil
+}
@@ -0,0 +1,329 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// EVENT_GREP_MATCHES is sent to the calling window with a GrepBatch for every
+// file that matched.
+const EVENT_GREP_MATCHES = "grep:matches"
+
+// GREP_MAX_MATCHES stops a search when the caller gives no limit.
+const GREP_MAX_MATCHES = 2000
+
+// GREP_MAX_FILE_SIZE skips files too large to be notes.
+const GREP_MAX_FILE_SIZE = 8 << 20
+
+// GREP_MAX_CONTEXT limits the context lines sent with each match.
+const GREP_MAX_CONTEXT = 10
+
+// APP_DIR is the folder of a notespace holding its config, state and undo
+// history. Like an ORDER_FILE it belongs to the app, so searches skip it.
+const APP_DIR = ".noted"
+
+// sniffSize is how much of a file is looked at to tell text from binary.
+const sniffSize = 8000
+
+type GrepOptions struct {
+	// ID is echoed in every event so a window can ignore batches of older searches.
+	ID            string `json:"id"`
+	Pattern       string `json:"pattern"`
+	Regex         bool   `json:"regex"`
+	CaseSensitive bool   `json:"caseSensitive"`
+	WholeWord     bool   `json:"wholeWord"`
+	// Include and Exclude are globs. Globs without a slash match file or folder
+	// names at any depth; others match the path relative to the root, where **
+	// spans folders. An empty Include searches every file.
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+	// Context is the number of lines sent before and after each match.
+	Context    int `json:"context"`
+	MaxMatches int `json:"maxMatches"`
+}
+
+// GrepRange is a match within a line, in characters from the start of the line.
+type GrepRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// GrepMatch is a matching line. Line and Column are 1-based.
+type GrepMatch struct {
+	Line   int         `json:"line"`
+	Column int         `json:"column"`
+	Text   string      `json:"text"`
+	Ranges []GrepRange `json:"ranges"`
+	Before []string    `json:"before"`
+	After  []string    `json:"after"`
+}
+
+type GrepBatch struct {
+	ID      string      `json:"id"`
+	Path    string      `json:"path"`
+	Matches []GrepMatch `json:"matches"`
+}
+
+// GrepSummary is returned once a search has finished.
+type GrepSummary struct {
+	ID           string `json:"id"`
+	Files        int    `json:"files"`
+	MatchedFiles int    `json:"matchedFiles"`
+	Matches      int    `json:"matches"`
+	// Truncated is set when the search stopped at MaxMatches.
+	Truncated bool `json:"truncated"`
+}
+
+// Grep searches the files below root, skipping what the file tree skips and
+// binary files. Matches are streamed to the calling window as EVENT_GREP_MATCHES
+// while the search runs; cancelling the call stops it.
+func (s *Scanner) Grep(ctx context.Context, root string, opts GrepOptions) (GrepSummary, error) {
+	summary := GrepSummary{ID: opts.ID}
+
+	pattern, include, exclude, err := compileSearch(opts)
+	if err != nil {
+		return summary, err
+	}
+
+	maxMatches := opts.MaxMatches
+	if maxMatches <= 0 {
+		maxMatches = GREP_MAX_MATCHES
+	}
+	contextLines := max(0, min(opts.Context, GREP_MAX_CONTEXT))
+
+	emit := func(batch GrepBatch) {
+		application.Get().Event.Emit(EVENT_GREP_MATCHES, batch)
+	}
+	if window, ok := ctx.Value(application.WindowKey).(application.Window); ok && window != nil {
+		emit = func(batch GrepBatch) {
+			window.DispatchWailsEvent(&application.CustomEvent{Name: EVENT_GREP_MATCHES, Data: batch})
+		}
+	}
+
+	err = s.walkText(ctx, root, include, exclude, func(path string) error {
+		matches := grepFile(path, pattern, contextLines, maxMatches-summary.Matches)
+		summary.Files++
+		if len(matches) == 0 {
+			return nil
+		}
+		summary.MatchedFiles++
+		summary.Matches += len(matches)
+		emit(GrepBatch{ID: opts.ID, Path: path, Matches: matches})
+
+		if summary.Matches >= maxMatches {
+			summary.Truncated = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return summary, ctx.Err()
+		}
+		log.Printf("grep in %s failed: %v", root, err)
+		return summary, status.FromOS(err, root, status.FAILED_TREE_SCAN)
+	}
+	return summary, nil
+}
+
+// walkText calls visit with every file below root that a search looks at: the
+// notes the file tree shows, without app metadata, filtered by the include and
+// exclude globs. visit may return filepath.SkipAll to stop early.
+func (s *Scanner) walkText(ctx context.Context, root string, include []*regexp.Regexp, exclude []*regexp.Regexp, visit func(path string) error) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if path == root {
+			return nil
+		}
+
+		rel := filepath.ToSlash(strings.TrimPrefix(path, root+string(filepath.Separator)))
+		if entry.IsDir() {
+			if (!s.IncludeHidden && isHiddenName(entry.Name())) || shouldPrune(entry.Name(), s.PruneDirNames) || matchGlobs(exclude, rel) {
+				return filepath.SkipDir
+			}
+			// Also keeps the old copies of notes in replace journals out
+			if entry.Name() == APP_DIR {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || (!s.IncludeHidden && isHiddenName(entry.Name())) || entry.Name() == ORDER_FILE {
+			return nil
+		}
+		if matchGlobs(exclude, rel) || (len(include) > 0 && !matchGlobs(include, rel)) {
+			return nil
+		}
+		return visit(path)
+	})
+}
+
+// compileSearch compiles the pattern and globs of opts.
+func compileSearch(opts GrepOptions) (pattern *regexp.Regexp, include []*regexp.Regexp, exclude []*regexp.Regexp, err error) {
+	if pattern, err = compileGrep(opts); err != nil {
+		return nil, nil, nil, status.New(status.FAILED_GREP_PATTERN, "", err)
+	}
+	if include, err = compileGlobs(opts.Include); err != nil {
+		return nil, nil, nil, status.New(status.FAILED_GREP_PATTERN, "", err)
+	}
+	if exclude, err = compileGlobs(opts.Exclude); err != nil {
+		return nil, nil, nil, status.New(status.FAILED_GREP_PATTERN, "", err)
+	}
+	return pattern, include, exclude, nil
+}
+
+// compileGrep turns the pattern options into one regular expression.
+func compileGrep(opts GrepOptions) (*regexp.Regexp, error) {
+	if opts.Pattern == "" {
+		return nil, errors.New("empty pattern")
+	}
+
+	expr := opts.Pattern
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if opts.WholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !opts.CaseSensitive {
+		expr = `(?i)` + expr
+	}
+	return regexp.Compile(expr)
+}
+
+// grepFile returns up to limit matching lines of a text file.
+func grepFile(path string, pattern *regexp.Regexp, contextLines int, limit int) []GrepMatch {
+	data, ok := readText(path)
+	if !ok {
+		return nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+
+	matches := []GrepMatch{}
+	for i, line := range lines {
+		if len(matches) >= limit {
+			break
+		}
+		locations := pattern.FindAllStringIndex(line, -1)
+		if len(locations) == 0 {
+			continue
+		}
+
+		match := GrepMatch{
+			Line:   i + 1,
+			Text:   line,
+			Ranges: make([]GrepRange, 0, len(locations)),
+			Before: lines[max(0, i-contextLines):i],
+			After:  lines[i+1 : min(len(lines), i+1+contextLines)],
+		}
+		for _, location := range locations {
+			// Empty matches, e.g. of ^, carry no text to highlight
+			if location[0] == location[1] {
+				continue
+			}
+			match.Ranges = append(match.Ranges, GrepRange{
+				Start: utf8.RuneCountInString(line[:location[0]]),
+				End:   utf8.RuneCountInString(line[:location[1]]),
+			})
+		}
+		if len(match.Ranges) == 0 {
+			start := utf8.RuneCountInString(line[:locations[0][0]])
+			match.Ranges = append(match.Ranges, GrepRange{Start: start, End: start})
+		}
+		match.Column = match.Ranges[0].Start + 1
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// readText returns the content of path unless it is too large or binary.
+func readText(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > GREP_MAX_FILE_SIZE {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(data[:min(len(data), sniffSize)], 0) >= 0 {
+		return nil, false
+	}
+	return data, true
+}
+
+// compileGlobs converts globs to regular expressions over slash-separated
+// relative paths.
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	compiled := []*regexp.Regexp{}
+	for _, glob := range globs {
+		glob = strings.TrimSpace(glob)
+		if glob == "" {
+			continue
+		}
+
+		var expr strings.Builder
+		// Globs without a slash match a name at any depth
+		if !strings.Contains(strings.TrimSuffix(glob, "/"), "/") {
+			expr.WriteString(`^(?:.*/)?`)
+		} else {
+			expr.WriteString(`^`)
+			glob = strings.TrimPrefix(glob, "/")
+		}
+		glob = strings.TrimSuffix(glob, "/")
+
+		for i := 0; i < len(glob); i++ {
+			switch c := glob[i]; {
+			case strings.HasPrefix(glob[i:], "**/"):
+				expr.WriteString(`(?:.*/)?`)
+				i += 2
+			case strings.HasPrefix(glob[i:], "**"):
+				expr.WriteString(`.*`)
+				i++
+			case c == '*':
+				expr.WriteString(`[^/]*`)
+			case c == '?':
+				expr.WriteString(`[^/]`)
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		// A glob matching a folder also matches everything inside it
+		expr.WriteString(`(?:/.*)?$`)
+
+		re, err := regexp.Compile(expr.String())
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchGlobs(globs []*regexp.Regexp, rel string) bool {
+	for _, glob := range globs {
+		if glob.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
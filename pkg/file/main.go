@@ -1,13 +1,16 @@
 package file
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"noted/pkg/status"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,6 +20,7 @@ type Node struct {
 	Type      string    `json:"type"` // "file" | "dir" | "symlink"
 	Size      int64     `json:"size"`
 	Modified  time.Time `json:"modified"`
+	Created   time.Time `json:"created"`
 	Children  []Node    `json:"children,omitempty"`
 	IsHidden  bool      `json:"isHidden"`
 	Extension string    `json:"extension,omitempty"`
@@ -29,20 +33,36 @@ type Scanner struct {
 	// and, if FollowSymlinkDirs is true, traverse into symlinked directories safely.
 	ResolveSymlinks   bool
 	FollowSymlinkDirs bool
-	PruneDirNames     []string // names to skip at directory boundaries, e.g., {"node_modules", ".git"}
-	IncludeHidden     bool     // if false, skip hidden files/dirs (dot-prefixed on Unix; system attributes on Windows best-effort)
-	AbsolutePaths     bool     // if true, Path will be absolute; otherwise Paths are relative to root
-	MaxDepth          int      // 0 means unlimited; 1 means only root; 2 includes root children, etc.
+	PruneDirNames     []string   // names to skip at directory boundaries, e.g., {"node_modules", ".git"}
+	IncludeHidden     bool       // if false, skip hidden files/dirs (dot-prefixed on Unix; system attributes on Windows best-effort)
+	AbsolutePaths     bool       // if true, Path will be absolute; otherwise Paths are relative to root
+	MaxDepth          int        // 0 means unlimited; 1 means only root; 2 includes root children, etc.
+	SortMode          SortMode   // order of children; folders may override it with an ORDER_FILE
+	Guard             WriteGuard // if set, asked before every change made on behalf of a window
+	Saved             SaveHook   // if set, told about every file written on behalf of a window
+
+	// mu guards SortMode, which SetSortMode changes while other calls scan.
+	mu sync.RWMutex
 }
 
+// WriteGuard reports why the window behind ctx may not change path, if it may not.
+type WriteGuard func(ctx context.Context, path string) error
+
+// SaveHook is called with the path of a file after it was written.
+type SaveHook func(path string)
+
+// DefaultPruneDirNames are skipped by the scanner and by anything else walking a notespace.
+var DefaultPruneDirNames = []string{"node_modules", ".git"}
+
 func newScanner() *Scanner {
 	return &Scanner{
 		ResolveSymlinks:   true,
 		FollowSymlinkDirs: false, // set true to traverse into symlinked directories with cycle protection
-		PruneDirNames:     []string{"node_modules", ".git"},
+		PruneDirNames:     DefaultPruneDirNames,
 		IncludeHidden:     true,
 		AbsolutePaths:     true,
 		MaxDepth:          6,
+		SortMode:          SortNatural,
 	}
 }
 
@@ -53,45 +73,59 @@ func (s *Scanner) GetFileTree(root string) (Node, error) {
 	if s.AbsolutePaths {
 		base, err = filepath.Abs(root)
 		if err != nil {
-			return Node{}, err
+			return Node{}, status.New(status.FAILED_TREE_SCAN, root, err)
 		}
 	} else {
 		base = filepath.Clean(root)
 	}
 
 	visited := make(map[string]struct{}) // for cycle detection when following symlinks
-	return s.buildNode(base, base, 0, visited)
+	node, err := s.buildNode(base, base, 0, visited)
+	if err != nil {
+		return node, status.FromOS(err, root, status.FAILED_TREE_SCAN)
+	}
+	return node, nil
 }
 
 func (s *Scanner) GetFileData(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		log.Printf("failed to load file \"%s\" error: %v", path, err)
-		return "", err
+		return "", status.FromOS(err, path, status.FAILED_FILE_READ)
 	}
 
 	return string(data), nil
 }
 
-func (s *Scanner) SaveFileData(path string, content string) error {
+func (s *Scanner) SaveFileData(ctx context.Context, path string, content string) error {
+	if err := s.checkWrite(ctx, path); err != nil {
+		return err
+	}
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		log.Printf("failed to save file \"%s\" error: %v", path, err)
-		return err
+		return status.FromOS(err, path, status.FAILED_FILE_WRITE)
 	}
+	s.saved(path)
 
 	return nil
 }
 
-func (s *Scanner) CreateNewDir(path string) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		log.Printf("failed to create parent directory: %w", err)
+func (s *Scanner) CreateNewDir(ctx context.Context, path string) error {
+	if err := s.checkWrite(ctx, path); err != nil {
 		return err
 	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Printf("failed to create parent directory: %v", err)
+		return status.FromOS(err, path, status.FAILED_DIR_CREATE)
+	}
 	return nil
 }
 
-func (s *Scanner) CreateNewFile(path string, content string) error {
-	if err := s.CreateNewDir(filepath.Dir(path)); err != nil {
+func (s *Scanner) CreateNewFile(ctx context.Context, path string, content string) error {
+	if err := s.checkWrite(ctx, path); err != nil {
+		return err
+	}
+	if err := s.CreateNewDir(ctx, filepath.Dir(path)); err != nil {
 		return err
 	}
 
@@ -99,21 +133,35 @@ func (s *Scanner) CreateNewFile(path string, content string) error {
 	f, err := os.Create(path)
 	if err != nil {
 		fmt.Printf("failed to create file: %v", err)
-		return err
+		return status.FromOS(err, path, status.FAILED_FILE_CREATE)
 	}
 	defer f.Close()
 
 	// Optionally write initial content
 	if _, err := f.WriteString(content); err != nil {
 		fmt.Printf("failed to write to file: %v", err)
-		return err
+		return status.FromOS(err, path, status.FAILED_FILE_WRITE)
 	}
 
 	fmt.Println("File created:", filepath.Clean(path))
+	s.saved(path)
 
 	return nil
 }
 
+func (s *Scanner) checkWrite(ctx context.Context, path string) error {
+	if s.Guard == nil {
+		return nil
+	}
+	return s.Guard(ctx, path)
+}
+
+func (s *Scanner) saved(path string) {
+	if s.Saved != nil {
+		s.Saved(path)
+	}
+}
+
 // buildNode constructs a Node for the given path; rootBase is the anchor for relative paths.
 func (s *Scanner) buildNode(path string, rootBase string, depth int, visited map[string]struct{}) (Node, error) {
 	entryLstat, err := os.Lstat(path)
@@ -135,6 +183,7 @@ func (s *Scanner) buildNode(path string, rootBase string, depth int, visited map
 		Path:     formatPath(path, rootBase, s.AbsolutePaths),
 		Size:     safeSize(entryLstat),
 		Modified: entryLstat.ModTime(),
+		Created:  createdTime(entryLstat),
 		IsHidden: isHiddenName(filepath.Base(path)),
 		Extension: func(name string, isDir bool) string {
 			if isDir {
@@ -244,6 +293,11 @@ func (s *Scanner) listDirChildren(dir string, rootBase string, depth int, visite
 			continue
 		}
 
+		// Order files are app metadata, not notes
+		if !e.IsDir() && e.Name() == ORDER_FILE {
+			continue
+		}
+
 		childNode, cerr := s.buildNode(childPath, rootBase, depth, visited)
 		if cerr != nil {
 			// Skip unreadable entries but continue walking
@@ -259,6 +313,8 @@ func (s *Scanner) listDirChildren(dir string, rootBase string, depth int, visite
 		// If MaxDepth is set and exceeded, buildNode already limited children
 		children = append(children, childNode)
 	}
+
+	s.sortChildren(dir, children)
 	return children, nil
 }
 
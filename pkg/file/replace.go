@@ -0,0 +1,405 @@
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// REPLACE_JOURNAL_DIR holds one journal per applied replacement, relative to the
+// notespace root. Journals are local undo history, so the folder ignores itself.
+const REPLACE_JOURNAL_DIR = "/.noted/replace"
+
+// REPLACE_JOURNAL_LIMIT is how many replacements can be undone; older journals
+// are deleted.
+const REPLACE_JOURNAL_LIMIT = 20
+
+type ReplaceOptions struct {
+	Search GrepOptions `json:"search"`
+	// Replacement may refer to groups as $1 or ${name} when Search.Regex is set;
+	// otherwise it is inserted as written.
+	Replacement string `json:"replacement"`
+}
+
+// ReplaceLine is a line that a replacement changes. Ranges are the matches in
+// Before, in characters.
+type ReplaceLine struct {
+	Line   int         `json:"line"`
+	Before string      `json:"before"`
+	After  string      `json:"after"`
+	Ranges []GrepRange `json:"ranges"`
+}
+
+// ReplaceFile is the preview of one file. Hash identifies the content the
+// preview was made from; applying fails if the file changed since.
+type ReplaceFile struct {
+	Path  string        `json:"path"`
+	Hash  string        `json:"hash"`
+	Lines []ReplaceLine `json:"lines"`
+}
+
+type ReplacePreview struct {
+	Files   []ReplaceFile `json:"files"`
+	Matches int           `json:"matches"`
+	// Truncated is set when the preview stopped at Search.MaxMatches. Only
+	// previewed files can be replaced.
+	Truncated bool `json:"truncated"`
+}
+
+// ReplaceSelection picks the lines of a previewed file to change. No lines
+// selects all of them.
+type ReplaceSelection struct {
+	Path  string `json:"path"`
+	Hash  string `json:"hash"`
+	Lines []int  `json:"lines"`
+}
+
+// ReplaceRecord describes an applied replacement. Pass ID to UndoReplace to
+// revert it.
+type ReplaceRecord struct {
+	ID          string    `json:"id"`
+	Time        time.Time `json:"time"`
+	Pattern     string    `json:"pattern"`
+	Replacement string    `json:"replacement"`
+	Files       []string  `json:"files"`
+	Lines       int       `json:"lines"`
+}
+
+// journal is what is written to REPLACE_JOURNAL_DIR for every replacement.
+type journal struct {
+	ReplaceRecord
+	Changes []journalChange `json:"changes"`
+}
+
+// journalChange holds the whole previous content of a file. Hash is that of the
+// replaced content, so an undo can tell whether the file was edited since.
+type journalChange struct {
+	Path   string `json:"path"`
+	Before string `json:"before"`
+	Hash   string `json:"hash"`
+}
+
+// PreviewReplace returns the lines that replacing opts.Search with
+// opts.Replacement below root would change, without changing anything.
+func (s *Scanner) PreviewReplace(ctx context.Context, root string, opts ReplaceOptions) (ReplacePreview, error) {
+	preview := ReplacePreview{Files: []ReplaceFile{}}
+
+	pattern, include, exclude, err := compileSearch(opts.Search)
+	if err != nil {
+		return preview, err
+	}
+	maxMatches := opts.Search.MaxMatches
+	if maxMatches <= 0 {
+		maxMatches = GREP_MAX_MATCHES
+	}
+
+	err = s.walkText(ctx, root, include, exclude, func(path string) error {
+		data, ok := readText(path)
+		if !ok {
+			return nil
+		}
+		lines := replaceLines(string(data), pattern, opts, nil)
+		if len(lines) == 0 {
+			return nil
+		}
+
+		preview.Files = append(preview.Files, ReplaceFile{Path: path, Hash: hash(data), Lines: lines})
+		for _, line := range lines {
+			preview.Matches += len(line.Ranges)
+		}
+		if preview.Matches >= maxMatches {
+			preview.Truncated = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return preview, ctx.Err()
+		}
+		log.Printf("replace preview in %s failed: %v", root, err)
+		return preview, status.FromOS(err, root, status.FAILED_TREE_SCAN)
+	}
+	return preview, nil
+}
+
+// ApplyReplace changes the selected lines of previewed files. Either every file
+// is written or, if one fails, none is. A journal is kept so that the whole
+// replacement can be reverted with UndoReplace.
+func (s *Scanner) ApplyReplace(ctx context.Context, root string, opts ReplaceOptions, selection []ReplaceSelection) (ReplaceRecord, error) {
+	record := ReplaceRecord{
+		ID:          time.Now().UTC().Format("20060102T150405.000000000"),
+		Time:        time.Now(),
+		Pattern:     opts.Search.Pattern,
+		Replacement: opts.Replacement,
+		Files:       []string{},
+	}
+
+	pattern, _, _, err := compileSearch(opts.Search)
+	if err != nil {
+		return record, err
+	}
+
+	j := journal{Changes: []journalChange{}}
+	contents := map[string]string{}
+	for _, selected := range selection {
+		path := filepath.Clean(selected.Path)
+		if _, ok := contents[path]; ok {
+			continue
+		}
+		if !within(root, path) {
+			return record, status.New(status.FAILED_REPLACE, path, errors.New("outside of the notespace"))
+		}
+		if isMetadata(root, path) {
+			return record, status.New(status.FAILED_REPLACE, path, errors.New("not a note"))
+		}
+		if err := s.checkWrite(ctx, path); err != nil {
+			return record, err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return record, status.FromOS(err, path, status.FAILED_FILE_READ)
+		}
+		if hash(data) != selected.Hash {
+			return record, status.New(status.FAILED_REPLACE_CHANGED, path, nil)
+		}
+
+		lines := replaceLines(string(data), pattern, opts, selected.Lines)
+		if len(lines) == 0 {
+			continue
+		}
+		after := applyLines(string(data), lines)
+
+		contents[path] = after
+		j.Changes = append(j.Changes, journalChange{Path: path, Before: string(data), Hash: hash([]byte(after))})
+		record.Files = append(record.Files, path)
+		record.Lines += len(lines)
+	}
+	if len(j.Changes) == 0 {
+		return record, nil
+	}
+
+	// The journal goes first: if writing stops halfway, it can still be undone
+	j.ReplaceRecord = record
+	if err := writeJournal(root, j); err != nil {
+		log.Printf("failed to write replace journal in %s: %v", root, err)
+		return record, status.FromOS(err, root, status.FAILED_REPLACE)
+	}
+	if err := s.writeAll(j.Changes, func(change journalChange) string { return contents[change.Path] }); err != nil {
+		os.Remove(journalPath(root, record.ID))
+		return record, err
+	}
+
+	pruneJournals(root)
+	log.Printf("Replaced %d lines in %d files of %s", record.Lines, len(record.Files), root)
+	return record, nil
+}
+
+// UndoReplace restores the files changed by the replacement id, all or none.
+// It fails if any of them was edited after the replacement.
+func (s *Scanner) UndoReplace(ctx context.Context, root string, id string) (ReplaceRecord, error) {
+	j, err := readJournal(root, id)
+	if err != nil {
+		return ReplaceRecord{}, status.FromOS(err, journalPath(root, id), status.FAILED_REPLACE_UNDO)
+	}
+
+	for _, change := range j.Changes {
+		if err := s.checkWrite(ctx, change.Path); err != nil {
+			return j.ReplaceRecord, err
+		}
+		data, err := os.ReadFile(change.Path)
+		if err != nil {
+			return j.ReplaceRecord, status.FromOS(err, change.Path, status.FAILED_REPLACE_UNDO)
+		}
+		if hash(data) != change.Hash {
+			return j.ReplaceRecord, status.New(status.FAILED_REPLACE_UNDO, change.Path, errors.New("the file was edited after the replacement"))
+		}
+	}
+
+	if err := s.writeAll(j.Changes, func(change journalChange) string { return change.Before }); err != nil {
+		return j.ReplaceRecord, err
+	}
+	if err := os.Remove(journalPath(root, id)); err != nil {
+		log.Printf("failed to remove replace journal %s: %v", id, err)
+	}
+	log.Printf("Undid replacement %s in %s", id, root)
+	return j.ReplaceRecord, nil
+}
+
+// ReplaceHistory lists the replacements of root that can be undone, newest first.
+func (s *Scanner) ReplaceHistory(root string) ([]ReplaceRecord, error) {
+	records := []ReplaceRecord{}
+	for _, id := range journalIDs(root) {
+		j, err := readJournal(root, id)
+		if err != nil {
+			log.Printf("Skipping replace journal %s: %v", id, err)
+			continue
+		}
+		records = append(records, j.ReplaceRecord)
+	}
+	return records, nil
+}
+
+// isMetadata reports whether path is app metadata that a replacement must not
+// touch: anything in an APP_DIR, or an ORDER_FILE.
+func isMetadata(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	return slices.Contains(parts[:len(parts)-1], APP_DIR) || parts[len(parts)-1] == ORDER_FILE
+}
+
+// writeAll writes the content returned by content to every changed file, all or
+// none, and reports them to the save hook.
+func (s *Scanner) writeAll(changes []journalChange, content func(journalChange) string) error {
+	paths := make([]string, len(changes))
+	byPath := make(map[string]journalChange, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+		byPath[change.Path] = change
+	}
+	if err := WriteAllAtomic(paths, func(path string) []byte { return []byte(content(byPath[path])) }); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		s.saved(path)
+	}
+	return nil
+}
+
+// replaceLines returns the lines of content that the replacement changes,
+// limited to the 1-based line numbers in only unless it is empty.
+func replaceLines(content string, pattern *regexp.Regexp, opts ReplaceOptions, only []int) []ReplaceLine {
+	changed := []ReplaceLine{}
+	for i, line := range strings.Split(content, "\n") {
+		if len(only) > 0 && !slices.Contains(only, i+1) {
+			continue
+		}
+		line = strings.TrimSuffix(line, "\r")
+		locations := pattern.FindAllStringIndex(line, -1)
+		if len(locations) == 0 {
+			continue
+		}
+
+		var after string
+		if opts.Search.Regex {
+			after = pattern.ReplaceAllString(line, opts.Replacement)
+		} else {
+			after = pattern.ReplaceAllLiteralString(line, opts.Replacement)
+		}
+		if after == line {
+			continue
+		}
+
+		ranges := make([]GrepRange, 0, len(locations))
+		for _, location := range locations {
+			ranges = append(ranges, GrepRange{
+				Start: utf8.RuneCountInString(line[:location[0]]),
+				End:   utf8.RuneCountInString(line[:location[1]]),
+			})
+		}
+		changed = append(changed, ReplaceLine{Line: i + 1, Before: line, After: after, Ranges: ranges})
+	}
+	return changed
+}
+
+// applyLines puts the changed lines into content, keeping its line endings.
+func applyLines(content string, changed []ReplaceLine) string {
+	lines := strings.Split(content, "\n")
+	for _, line := range changed {
+		if strings.HasSuffix(lines[line.Line-1], "\r") {
+			line.After += "\r"
+		}
+		lines[line.Line-1] = line.After
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func within(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func journalPath(root string, id string) string {
+	return filepath.Join(root, REPLACE_JOURNAL_DIR, id+".json")
+}
+
+func writeJournal(root string, j journal) error {
+	dir := filepath.Join(root, REPLACE_JOURNAL_DIR)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0o644); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return WriteAtomic(journalPath(root, j.ID), data)
+}
+
+func readJournal(root string, id string) (journal, error) {
+	var j journal
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return j, fmt.Errorf("invalid replacement id %q", id)
+	}
+	data, err := os.ReadFile(journalPath(root, id))
+	if err != nil {
+		return j, err
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return j, err
+	}
+	return j, nil
+}
+
+// journalIDs lists the journals of root, newest first. IDs sort by time.
+func journalIDs(root string) []string {
+	entries, err := os.ReadDir(filepath.Join(root, REPLACE_JOURNAL_DIR))
+	if err != nil {
+		return nil
+	}
+	ids := []string{}
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	slices.Reverse(ids)
+	return ids
+}
+
+func pruneJournals(root string) {
+	ids := journalIDs(root)
+	for _, id := range ids[min(len(ids), REPLACE_JOURNAL_LIMIT):] {
+		if err := os.Remove(journalPath(root, id)); err != nil {
+			log.Printf("failed to remove replace journal %s: %v", id, err)
+		}
+	}
+}
@@ -1,7 +1,46 @@
 package file
 
-import "github.com/wailsapp/wails/v3/pkg/application"
+import (
+	"log"
+	"noted/pkg/status"
 
-func Service() application.Service {
-	return application.NewService(newScanner())
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// Service binds the scanner. guard, if not nil, may refuse changes requested by a
+// window; saved, if not nil, is told about every file written.
+func Service(sortMode SortMode, guard WriteGuard, saved SaveHook) application.Service {
+	scanner := newScanner()
+	scanner.Guard = guard
+	scanner.Saved = saved
+	if err := scanner.SetSortMode(sortMode); err != nil {
+		log.Printf("Ignoring sort mode preference: %v", err)
+	}
+
+	return application.NewServiceWithOptions(scanner, application.ServiceOptions{
+		MarshalError: status.MarshalError,
+	})
+}
+
+// SortModeSetter returns the SetSortMode of the scanner bound by service, so a
+// changed preference applies to the running app.
+func SortModeSetter(service application.Service) func(mode SortMode) error {
+	s, ok := service.Instance().(*Scanner)
+	if !ok {
+		return nil
+	}
+	return s.SetSortMode
+}
+
+// Tree scans root the way the app does, without a running app. maxDepth 0 keeps
+// the scanner's default depth.
+func Tree(root string, sortMode SortMode, maxDepth int) (Node, error) {
+	scanner := newScanner()
+	if err := scanner.SetSortMode(sortMode); err != nil {
+		return Node{}, err
+	}
+	if maxDepth > 0 {
+		scanner.MaxDepth = maxDepth
+	}
+	return scanner.GetFileTree(root)
 }
@@ -0,0 +1,272 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ORDER_FILE is the per-folder override kept next to the notes it orders.
+const ORDER_FILE = ".noted-order.json"
+
+type SortMode string
+
+const (
+	SortNatural   SortMode = "natural"    // "2-bar" before "10-foo", case-insensitive
+	SortDirsFirst SortMode = "dirs-first" // directories first, then natural
+	SortModified  SortMode = "modified"   // most recently modified first
+	SortCreated   SortMode = "created"    // most recently created first
+	SortTitle     SortMode = "title"      // frontmatter title, falling back to the name
+	SortManual    SortMode = "manual"     // only valid in an order file; entries listed there first
+)
+
+// DirOrder is the content of an ORDER_FILE.
+type DirOrder struct {
+	// Sort overrides the scanner's mode for this folder only. Empty keeps the scanner's mode.
+	Sort SortMode `json:"sort,omitempty"`
+	// Order lists entry names that go first, in the given order. Unlisted entries follow,
+	// sorted by the folder's mode.
+	Order []string `json:"order,omitempty"`
+}
+
+func (m SortMode) valid() bool {
+	switch m {
+	case SortNatural, SortDirsFirst, SortModified, SortCreated, SortTitle, SortManual:
+		return true
+	}
+	return false
+}
+
+// CheckSortMode reports why mode cannot be the order of a whole tree, if it
+// cannot. SortManual only makes sense in an ORDER_FILE.
+func CheckSortMode(mode SortMode) error {
+	if !mode.valid() || mode == SortManual {
+		return status.New(status.FAILED_SORT_MODE, "", fmt.Errorf("unknown sort mode %q", mode))
+	}
+	return nil
+}
+
+// SetSortMode changes the order used by GetFileTree for folders without an override.
+func (s *Scanner) SetSortMode(mode SortMode) error {
+	if err := CheckSortMode(mode); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.SortMode = mode
+	s.mu.Unlock()
+	return nil
+}
+
+// GetDirOrder returns the override stored in dir, or an empty order if there is none.
+func (s *Scanner) GetDirOrder(dir string) (DirOrder, error) {
+	order, err := readDirOrder(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return DirOrder{}, status.FromOS(err, filepath.Join(dir, ORDER_FILE), status.FAILED_ORDER_FILE)
+	}
+	return order, nil
+}
+
+// SaveDirOrder writes the override for dir. An empty order removes the file.
+func (s *Scanner) SaveDirOrder(ctx context.Context, dir string, order DirOrder) error {
+	path := filepath.Join(dir, ORDER_FILE)
+	if order.Sort != "" && !order.Sort.valid() {
+		return status.New(status.FAILED_SORT_MODE, path, fmt.Errorf("unknown sort mode %q", order.Sort))
+	}
+
+	if err := s.checkWrite(ctx, path); err != nil {
+		return err
+	}
+
+	if order.Sort == "" && len(order.Order) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to remove order file \"%s\" error: %v", path, err)
+			return status.FromOS(err, path, status.FAILED_ORDER_FILE)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(order, "", "  ")
+	if err != nil {
+		return status.New(status.FAILED_ORDER_FILE, path, err)
+	}
+	return s.SaveFileData(ctx, path, string(data)+"\n")
+}
+
+func readDirOrder(dir string) (DirOrder, error) {
+	order := DirOrder{}
+	data, err := os.ReadFile(filepath.Join(dir, ORDER_FILE))
+	if err != nil {
+		return order, err
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return DirOrder{}, err
+	}
+	if order.Sort != "" && !order.Sort.valid() {
+		return DirOrder{}, fmt.Errorf("unknown sort mode %q", order.Sort)
+	}
+	return order, nil
+}
+
+// sortChildren orders the children of dir in place, honouring an ORDER_FILE if present.
+func (s *Scanner) sortChildren(dir string, children []Node) {
+	s.mu.RLock()
+	mode := s.SortMode
+	s.mu.RUnlock()
+	if mode == "" {
+		mode = SortNatural
+	}
+
+	order, err := readDirOrder(dir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("ignoring order file in \"%s\" error: %v", dir, err)
+	}
+	if order.Sort != "" {
+		mode = order.Sort
+	}
+
+	rank := make(map[string]int, len(order.Order))
+	for i, name := range order.Order {
+		if _, ok := rank[name]; !ok {
+			rank[name] = i
+		}
+	}
+
+	var titles map[string]string
+	if mode == SortTitle {
+		titles = make(map[string]string, len(children))
+		for _, child := range children {
+			titles[child.Name] = nodeTitle(dir, child)
+		}
+	}
+
+	slices.SortStableFunc(children, func(a, b Node) int {
+		ra, aListed := rank[a.Name]
+		rb, bListed := rank[b.Name]
+		switch {
+		case aListed && bListed:
+			return ra - rb
+		case aListed:
+			return -1
+		case bListed:
+			return 1
+		}
+
+		switch mode {
+		case SortDirsFirst:
+			if ad, bd := a.isDir(), b.isDir(); ad != bd {
+				if ad {
+					return -1
+				}
+				return 1
+			}
+		case SortModified:
+			if c := b.Modified.Compare(a.Modified); c != 0 {
+				return c
+			}
+		case SortCreated:
+			if c := b.Created.Compare(a.Created); c != 0 {
+				return c
+			}
+		case SortTitle:
+			if c := naturalCompare(titles[a.Name], titles[b.Name]); c != 0 {
+				return c
+			}
+		}
+		return naturalCompare(a.Name, b.Name)
+	})
+}
+
+func (n Node) isDir() bool {
+	return n.Type == "dir" || (n.Type == "symlink" && n.isSymlinkTargetDir)
+}
+
+// nodeTitle returns the frontmatter title of a markdown note, or its name otherwise.
+func nodeTitle(dir string, node Node) string {
+	if node.Type != "file" || node.Extension != ".md" {
+		return node.Name
+	}
+	if title := readFrontmatterTitle(filepath.Join(dir, node.Name)); title != "" {
+		return title
+	}
+	return strings.TrimSuffix(node.Name, filepath.Ext(node.Name))
+}
+
+// readFrontmatterTitle reads the `title:` key of a leading YAML frontmatter block
+// without loading the whole note.
+func readFrontmatterTitle(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return FrontmatterTitle(f)
+}
+
+// FrontmatterTitle returns the `title:` key of a leading YAML frontmatter block
+// in r, or "" if there is none.
+func FrontmatterTitle(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return ""
+	}
+	for lines := 0; scanner.Scan() && lines < 64; lines++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "title" {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return ""
+}
+
+// naturalCompare compares strings case-insensitively, treating runs of digits as numbers.
+func naturalCompare(a, b string) int {
+	ar, br := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+	for i < len(ar) && j < len(br) {
+		if unicode.IsDigit(ar[i]) && unicode.IsDigit(br[j]) {
+			si, sj := i, j
+			for i < len(ar) && unicode.IsDigit(ar[i]) {
+				i++
+			}
+			for j < len(br) && unicode.IsDigit(br[j]) {
+				j++
+			}
+			na := strings.TrimLeft(string(ar[si:i]), "0")
+			nb := strings.TrimLeft(string(br[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			continue
+		}
+		if ar[i] != br[j] {
+			if ar[i] < br[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	if c := (len(ar) - i) - (len(br) - j); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,260 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"noted/pkg/status"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Edit sets or removes one top-level key.
+type Edit struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+	// Remove deletes the key instead of setting it to Value.
+	Remove bool `json:"remove"`
+}
+
+// Apply makes edits to the frontmatter of content, in order. Only the lines of
+// the keys being set or removed change: the order of the other keys, comments
+// and the body are kept as they were. A new key goes after the others; a note
+// without frontmatter gets a YAML block. Removing the last key removes the
+// block.
+func Apply(content string, edits []Edit) (string, error) {
+	d := split(content)
+	if _, err := d.parse(); err != nil {
+		return "", err
+	}
+	for _, edit := range edits {
+		if strings.TrimSpace(edit.Key) == "" {
+			return "", status.New(status.FAILED_FRONTMATTER_EDIT, "", errors.New("empty key"))
+		}
+		if edit.Remove {
+			d.remove(edit.Key)
+		} else if err := d.set(edit.Key, edit.Value); err != nil {
+			return "", status.New(status.FAILED_FRONTMATTER_EDIT, edit.Key, err)
+		}
+	}
+
+	result := strings.Join(d.lines, "\n")
+	// Line surgery must never leave frontmatter that no longer parses
+	if _, err := Parse(result); err != nil {
+		return "", status.New(status.FAILED_FRONTMATTER_EDIT, "", err)
+	}
+	return result, nil
+}
+
+// Set sets one top-level key of the frontmatter of content.
+func Set(content string, key string, value any) (string, error) {
+	return Apply(content, []Edit{{Key: key, Value: value}})
+}
+
+// Remove deletes one top-level key of the frontmatter of content.
+func Remove(content string, key string) (string, error) {
+	return Apply(content, []Edit{{Key: key, Remove: true}})
+}
+
+func (d *document) set(key string, value any) error {
+	if d.open < 0 {
+		cr := d.cr()
+		d.lines = slices.Insert(d.lines, 0, "---"+cr, "---"+cr)
+		d.format, d.open, d.close = FormatYAML, 0, 1
+	}
+
+	rendered, err := d.render(key, normalize(value))
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(rendered, "\n"), "\n")
+	for i := range lines {
+		lines[i] += d.cr()
+	}
+
+	matching := []entry{}
+	for _, e := range d.entries() {
+		if e.key == key {
+			matching = append(matching, e)
+		}
+	}
+	if len(matching) == 0 || matching[0].table {
+		// Inline values must come before the first TOML table
+		d.removeEntries(matching)
+		at := d.insertion()
+		d.splice(at, at, lines)
+		return nil
+	}
+
+	first := matching[0]
+	if first.close-first.open == 1 && len(lines) == 1 {
+		lines[0] = strings.TrimSuffix(lines[0], "\r") + comment(d.lines[first.open])
+	}
+	d.removeEntries(matching[1:])
+	d.splice(first.open, first.close, lines)
+	return nil
+}
+
+func (d *document) remove(key string) {
+	if d.open < 0 {
+		return
+	}
+	matching := []entry{}
+	for _, e := range d.entries() {
+		if e.key == key {
+			matching = append(matching, e)
+		}
+	}
+	if len(matching) == 0 {
+		return
+	}
+	d.removeEntries(matching)
+
+	for _, line := range d.lines[d.open+1 : d.close] {
+		if strings.TrimSpace(line) != "" {
+			return
+		}
+	}
+	d.lines = slices.Delete(d.lines, d.open, d.close+1)
+	d.format, d.open, d.close = "", -1, -1
+}
+
+// removeEntries deletes the lines of entries, which must be in order.
+func (d *document) removeEntries(entries []entry) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		d.splice(entries[i].open, entries[i].close, nil)
+	}
+}
+
+// splice replaces the lines from open up to close with lines.
+func (d *document) splice(open int, close int, lines []string) {
+	d.lines = slices.Replace(d.lines, open, close, lines...)
+	d.close += len(lines) - (close - open)
+}
+
+// insertion returns the line a new key is inserted at: before the closing
+// delimiter, or in TOML before the first table and the comments above it.
+func (d *document) insertion() int {
+	if d.format != FormatTOML {
+		return d.close
+	}
+	for _, e := range d.entries() {
+		if !e.table {
+			continue
+		}
+		at := e.open
+		for at-1 > d.open {
+			above := strings.TrimSpace(d.lines[at-1])
+			if above != "" && !strings.HasPrefix(above, "#") {
+				break
+			}
+			at--
+		}
+		return at
+	}
+	return d.close
+}
+
+// render writes key and value in the format of the frontmatter, ending in a
+// newline.
+func (d *document) render(key string, value any) (string, error) {
+	if d.format == FormatTOML {
+		if value == nil {
+			return "", errors.New("TOML has no null value; remove the key instead")
+		}
+		var b bytes.Buffer
+		encoder := toml.NewEncoder(&b).SetTablesInline(true).SetArraysMultiline(false)
+		if err := encoder.Encode(map[string]any{key: value}); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	// Follow the note in indenting lists below their key or not
+	indent := true
+	for _, line := range d.lines[d.open+1 : d.close] {
+		if line = strings.TrimSuffix(line, "\r"); line == "-" || strings.HasPrefix(line, "- ") {
+			indent = false
+			break
+		}
+	}
+	data, err := yaml.MarshalWithOptions(
+		yaml.MapSlice{{Key: key, Value: value}},
+		yaml.Indent(2),
+		yaml.IndentSequence(indent),
+		yaml.UseLiteralStyleIfMultiline(true),
+	)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// normalize turns whole float64 numbers, which is how JSON numbers arrive, back
+// into integers so they are not written as 3.0.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case []any:
+		normalized := make([]any, len(v))
+		for i, item := range v {
+			normalized[i] = normalize(item)
+		}
+		return normalized
+	case map[string]any:
+		normalized := make(map[string]any, len(v))
+		for key, item := range v {
+			normalized[key] = normalize(item)
+		}
+		return normalized
+	}
+	return value
+}
+
+// cr returns "\r" if the note uses CRLF line endings.
+func (d *document) cr() string {
+	if strings.HasSuffix(d.lines[0], "\r") {
+		return "\r"
+	}
+	return ""
+}
+
+// comment returns the trailing comment of a single-line key, with the space
+// before it and the line ending, or just the line ending if there is none.
+func comment(line string) string {
+	cr := ""
+	if strings.HasSuffix(line, "\r") {
+		line, cr = strings.TrimSuffix(line, "\r"), "\r"
+	}
+	inQuote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuote != 0:
+			if c == '\\' && inQuote == '"' {
+				i++
+			} else if c == '\'' && inQuote == '\'' && i+1 < len(line) && line[i+1] == '\'' {
+				// '' is a quote inside a single-quoted YAML string
+				i++
+			} else if c == inQuote {
+				inQuote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t:=[{,", line[i-1]) >= 0):
+			// Quotes inside a plain scalar, as in it's, open nothing
+			inQuote = c
+		case c == '#' && i > 0 && (line[i-1] == ' ' || line[i-1] == '\t'):
+			start := i
+			for start > 0 && (line[start-1] == ' ' || line[start-1] == '\t') {
+				start--
+			}
+			return line[start:] + cr
+		}
+	}
+	return cr
+}
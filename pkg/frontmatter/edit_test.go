@@ -0,0 +1,224 @@
+package frontmatter
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edits   []Edit
+		want    string
+	}{
+		{
+			name:    "yaml set keeps comment",
+			content: "---\ntitle: Old # shown in the tree\ntags: [a]\n---\nbody\n",
+			edits:   []Edit{{Key: "title", Value: "New"}},
+			want:    "---\ntitle: New # shown in the tree\ntags: [a]\n---\nbody\n",
+		},
+		{
+			name:    "yaml set appends new key",
+			content: "---\ntitle: A\n---\nbody",
+			edits:   []Edit{{Key: "draft", Value: true}},
+			want:    "---\ntitle: A\ndraft: true\n---\nbody",
+		},
+		{
+			name:    "yaml set replaces indented list",
+			content: "---\ntags:\n  - a\n  - b\ntitle: A\n---\n",
+			edits:   []Edit{{Key: "tags", Value: []any{"c"}}},
+			want:    "---\ntags:\n  - c\ntitle: A\n---\n",
+		},
+		{
+			name:    "yaml set follows unindented list",
+			content: "---\ntags:\n- a\n- b\ntitle: A\n---\n",
+			edits:   []Edit{{Key: "tags", Value: []any{"c"}}},
+			want:    "---\ntags:\n- c\ntitle: A\n---\n",
+		},
+		{
+			name:    "yaml set whole float as integer",
+			content: "---\ntitle: A\n---\n",
+			edits:   []Edit{{Key: "rank", Value: 3.0}},
+			want:    "---\ntitle: A\nrank: 3\n---\n",
+		},
+		{
+			name:    "yaml set quoted key",
+			content: "---\n\"my key\": 1\n---\n",
+			edits:   []Edit{{Key: "my key", Value: 2}},
+			want:    "---\nmy key: 2\n---\n",
+		},
+		{
+			name:    "yaml remove block scalar with blank line",
+			content: "---\ndesc: |\n  one\n\n  two\ntitle: A\n---\n",
+			edits:   []Edit{{Key: "desc", Remove: true}},
+			want:    "---\ntitle: A\n---\n",
+		},
+		{
+			name:    "yaml remove keeps comments between keys",
+			content: "---\n# about\ntitle: A\n# the rest\ndraft: true\n---\n",
+			edits:   []Edit{{Key: "title", Remove: true}},
+			want:    "---\n# about\n# the rest\ndraft: true\n---\n",
+		},
+		{
+			name:    "remove last key removes block",
+			content: "---\ntitle: A\n---\nbody\n",
+			edits:   []Edit{{Key: "title", Remove: true}},
+			want:    "body\n",
+		},
+		{
+			name:    "remove missing key",
+			content: "---\ntitle: A\n---\n",
+			edits:   []Edit{{Key: "draft", Remove: true}},
+			want:    "---\ntitle: A\n---\n",
+		},
+		{
+			name:    "set without frontmatter adds yaml",
+			content: "body\n",
+			edits:   []Edit{{Key: "title", Value: "A"}},
+			want:    "---\ntitle: A\n---\nbody\n",
+		},
+		{
+			name:    "crlf set and add",
+			content: "---\r\ntitle: A\r\n---\r\nbody\r\n",
+			edits:   []Edit{{Key: "title", Value: "B"}, {Key: "draft", Value: false}},
+			want:    "---\r\ntitle: B\r\ndraft: false\r\n---\r\nbody\r\n",
+		},
+		{
+			name:    "crlf without frontmatter",
+			content: "body\r\n",
+			edits:   []Edit{{Key: "title", Value: "A"}},
+			want:    "---\r\ntitle: A\r\n---\r\nbody\r\n",
+		},
+		{
+			name:    "crlf remove last key",
+			content: "---\r\ntitle: A\r\n---\r\nbody\r\n",
+			edits:   []Edit{{Key: "title", Remove: true}},
+			want:    "body\r\n",
+		},
+		{
+			name:    "toml set keeps comment",
+			content: "+++\ntitle = \"A\" # shown in the tree\n+++\n",
+			edits:   []Edit{{Key: "title", Value: "B"}},
+			want:    "+++\ntitle = 'B' # shown in the tree\n+++\n",
+		},
+		{
+			name:    "toml new key goes before tables",
+			content: "+++\ntitle = \"A\"\n\n# who\n[owner]\nname = \"x\"\n+++\n",
+			edits:   []Edit{{Key: "draft", Value: true}},
+			want:    "+++\ntitle = \"A\"\ndraft = true\n\n# who\n[owner]\nname = \"x\"\n+++\n",
+		},
+		{
+			name:    "toml set replaces multi-line string",
+			content: "+++\ndesc = \"\"\"\none\ntwo\n\"\"\"\ntitle = \"A\"\n+++\n",
+			edits:   []Edit{{Key: "desc", Value: "x"}},
+			want:    "+++\ndesc = 'x'\ntitle = \"A\"\n+++\n",
+		},
+		{
+			name:    "toml set replaces multi-line array",
+			content: "+++\ntags = [\n  \"a\", # first\n  \"b]\",\n]\ntitle = \"A\"\n+++\n",
+			edits:   []Edit{{Key: "tags", Value: []any{"c"}}},
+			want:    "+++\ntags = ['c']\ntitle = \"A\"\n+++\n",
+		},
+		{
+			name:    "toml remove table",
+			content: "+++\ntitle = \"A\"\n[owner]\nname = \"x\"\n[links]\nhome = \"y\"\n+++\n",
+			edits:   []Edit{{Key: "owner", Remove: true}},
+			want:    "+++\ntitle = \"A\"\n[links]\nhome = \"y\"\n+++\n",
+		},
+		{
+			name:    "toml set table key inline",
+			content: "+++\ntitle = \"A\"\n[owner]\nname = \"x\"\n+++\n",
+			edits:   []Edit{{Key: "owner", Value: map[string]any{"name": "y"}}},
+			want:    "+++\ntitle = \"A\"\nowner = {name = 'y'}\n+++\n",
+		},
+		{
+			name:    "toml remove last key",
+			content: "+++\ntitle = \"A\"\n+++\nbody\n",
+			edits:   []Edit{{Key: "title", Remove: true}},
+			want:    "body\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Apply(test.content, test.edits)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Apply\n got %q\nwant %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edits   []Edit
+	}{
+		{"empty key", "---\ntitle: A\n---\n", []Edit{{Key: " ", Value: 1}}},
+		{"toml null", "+++\ntitle = \"A\"\n+++\n", []Edit{{Key: "title", Value: nil}}},
+		{"invalid frontmatter", "---\ntitle: [a\n---\n", []Edit{{Key: "draft", Value: true}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, err := Apply(test.content, test.edits); err == nil {
+				t.Errorf("Apply = %q, want an error", got)
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"none", "body\n", []string{}},
+		{"yaml", "---\ntitle: A\ntags:\n- a\n# note\ndraft: true\n---\n", []string{"title", "tags", "draft"}},
+		{"yaml dots", "---\ntitle: A\n...\nbody\n", []string{"title"}},
+		{"toml tables", "+++\ntitle = \"A\"\n[owner.info]\nx = 1\n[\"a.b\"]\ny = 2\n+++\n", []string{"title", "owner", "a.b"}},
+		{"toml multi-line", "+++\ndesc = '''\nfake = 1\n'''\ntitle = \"A\"\n+++\n", []string{"desc", "title"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matter, err := Parse(test.content)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !slices.Equal(matter.Keys, test.want) {
+				t.Errorf("Keys = %q, want %q", matter.Keys, test.want)
+			}
+		})
+	}
+}
+
+func TestComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"title: A", ""},
+		{"title: A # note", " # note"},
+		{"title: A\t# note\r", "\t# note\r"},
+		{"title: a#b", ""},
+		{`title: "a # b"`, ""},
+		{`title: "a \" # b" # note`, " # note"},
+		{"title: 'it''s # b' # note", " # note"},
+		{"title: it's # note", " # note"},
+		{`title = "A" # note`, " # note"},
+		{"tags = ['a # b'] # note", " # note"},
+	}
+
+	for _, test := range tests {
+		if got := comment(test.line); got != test.want {
+			t.Errorf("comment(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
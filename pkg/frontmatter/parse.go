@@ -0,0 +1,265 @@
+package frontmatter
+
+import (
+	"noted/pkg/status"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+)
+
+type Format string
+
+const (
+	// FormatYAML is frontmatter between --- lines.
+	FormatYAML Format = "yaml"
+	// FormatTOML is frontmatter between +++ lines.
+	FormatTOML Format = "toml"
+)
+
+// DELIMITERS are the lines that open and close frontmatter of each format.
+var DELIMITERS = map[Format]string{
+	FormatYAML: "---",
+	FormatTOML: "+++",
+}
+
+// Frontmatter is the metadata block at the top of a note.
+type Frontmatter struct {
+	// Format is empty when the note has no frontmatter.
+	Format Format         `json:"format"`
+	Data   map[string]any `json:"data"`
+	// Keys are the top-level keys in the order they are written.
+	Keys []string `json:"keys"`
+}
+
+// document is a note split into lines, which keep their "\r" if the note uses
+// CRLF line endings.
+type document struct {
+	lines  []string
+	format Format
+	// open and close are the indexes of the delimiter lines; both are -1 when
+	// there is no frontmatter.
+	open, close int
+}
+
+// entry is the lines of one top-level key, from open up to but not including
+// close.
+type entry struct {
+	key         string
+	open, close int
+	// table is set for TOML [tables], which hold their keys on the lines below.
+	table bool
+}
+
+// Parse reads the frontmatter of a note. A note without frontmatter gives an
+// empty Frontmatter, not an error.
+func Parse(content string) (Frontmatter, error) {
+	return split(content).parse()
+}
+
+func split(content string) document {
+	d := document{lines: strings.Split(content, "\n"), open: -1, close: -1}
+	first := strings.TrimSuffix(strings.TrimPrefix(d.lines[0], "\uFEFF"), "\r")
+	for format, delimiter := range DELIMITERS {
+		if strings.TrimRight(first, " \t") != delimiter {
+			continue
+		}
+		for i := 1; i < len(d.lines); i++ {
+			line := strings.TrimRight(d.lines[i], " \t\r")
+			if line == delimiter || (format == FormatYAML && line == "...") {
+				d.format, d.open, d.close = format, 0, i
+				return d
+			}
+		}
+	}
+	return d
+}
+
+func (d document) parse() (Frontmatter, error) {
+	matter := Frontmatter{Format: d.format, Data: map[string]any{}, Keys: []string{}}
+	if d.open < 0 {
+		return matter, nil
+	}
+
+	source := []byte(strings.Join(d.lines[d.open+1:d.close], "\n"))
+	var err error
+	switch d.format {
+	case FormatYAML:
+		err = yaml.Unmarshal(source, &matter.Data)
+	case FormatTOML:
+		err = toml.Unmarshal(source, &matter.Data)
+	}
+	if err != nil {
+		return Frontmatter{}, status.New(status.FAILED_FRONTMATTER, "", err)
+	}
+	if matter.Data == nil {
+		// An empty YAML document decodes to nil
+		matter.Data = map[string]any{}
+	}
+
+	for _, e := range d.entries() {
+		if _, ok := matter.Data[e.key]; ok && !slices.Contains(matter.Keys, e.key) {
+			matter.Keys = append(matter.Keys, e.key)
+		}
+	}
+	return matter, nil
+}
+
+// entries returns the top-level keys of the frontmatter with their lines.
+// Comments and blank lines between keys belong to no entry.
+func (d document) entries() []entry {
+	entries := []entry{}
+	for i := d.open + 1; i < d.close; i++ {
+		var e entry
+		var ok bool
+		if d.format == FormatTOML {
+			e, ok = d.tomlEntry(i)
+		} else {
+			e, ok = d.yamlEntry(i)
+		}
+		if ok {
+			entries = append(entries, e)
+			i = e.close - 1
+		}
+	}
+	return entries
+}
+
+// yamlEntry reads the key starting at line i, along with its indented value and
+// any sequence written at the same indentation as the key.
+func (d document) yamlEntry(i int) (entry, bool) {
+	line := strings.TrimSuffix(d.lines[i], "\r")
+	if line == "" || strings.ContainsAny(line[:1], " \t#-") {
+		return entry{}, false
+	}
+	key, ok := yamlKey(line)
+	if !ok {
+		return entry{}, false
+	}
+
+	e := entry{key: key, open: i, close: i + 1}
+	for j := i + 1; j < d.close; j++ {
+		next := strings.TrimSuffix(d.lines[j], "\r")
+		switch {
+		case strings.TrimSpace(next) == "":
+			// Blank lines belong to the value only if it goes on after them
+		case next[0] == ' ' || next[0] == '\t' || next == "-" || strings.HasPrefix(next, "- "):
+			e.close = j + 1
+		default:
+			return e, true
+		}
+	}
+	return e, true
+}
+
+// tomlEntry reads the key or [table] starting at line i. A table runs up to the
+// next one; a value runs on while its brackets or multi-line string are open.
+func (d document) tomlEntry(i int) (entry, bool) {
+	line := strings.TrimSpace(d.lines[i])
+	if line == "" || line[0] == '#' {
+		return entry{}, false
+	}
+
+	if line[0] == '[' {
+		name := strings.Trim(strings.TrimSpace(strings.SplitN(line, "]", 2)[0]), "[ ")
+		e := entry{key: unquote(tableKey(name)), open: i, close: d.close, table: true}
+		for j := i + 1; j < d.close; j++ {
+			if strings.HasPrefix(strings.TrimSpace(d.lines[j]), "[") {
+				e.close = j
+				break
+			}
+		}
+		return e, true
+	}
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return entry{}, false
+	}
+	e := entry{key: unquote(strings.TrimSpace(key)), open: i, close: i + 1}
+	value = strings.TrimSpace(value)
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, quote) && !strings.Contains(value[3:], quote) {
+			for j := i + 1; j < d.close; j++ {
+				if strings.Contains(d.lines[j], quote) {
+					e.close = j + 1
+					return e, true
+				}
+			}
+		}
+	}
+	depth := brackets(value)
+	for j := i + 1; j < d.close && depth > 0; j++ {
+		depth += brackets(d.lines[j])
+		e.close = j + 1
+	}
+	return e, true
+}
+
+// yamlKey returns the key of a "key: value" line, which may be quoted.
+func yamlKey(line string) (string, bool) {
+	if line[0] == '"' || line[0] == '\'' {
+		end := strings.IndexByte(line[1:], line[0])
+		if end < 0 || !strings.HasPrefix(strings.TrimLeft(line[end+2:], " "), ":") {
+			return "", false
+		}
+		return line[1 : end+1], true
+	}
+	for i := 0; i < len(line); i++ {
+		if line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t') {
+			return strings.TrimSpace(line[:i]), true
+		}
+	}
+	return "", false
+}
+
+// tableKey returns the top-level key of a dotted table name.
+func tableKey(name string) string {
+	inQuote := byte(0)
+	for i := 0; i < len(name); i++ {
+		switch {
+		case inQuote != 0 && name[i] == inQuote:
+			inQuote = 0
+		case inQuote == 0 && (name[i] == '"' || name[i] == '\''):
+			inQuote = name[i]
+		case inQuote == 0 && name[i] == '.':
+			return strings.TrimSpace(name[:i])
+		}
+	}
+	return name
+}
+
+// brackets returns how many more brackets and braces line opens than it
+// closes, outside strings and comments.
+func brackets(line string) int {
+	depth := 0
+	inQuote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuote != 0:
+			if c == '\\' && inQuote == '"' {
+				i++
+			} else if c == inQuote {
+				inQuote = 0
+			}
+		case c == '"' || c == '\'':
+			inQuote = c
+		case c == '#':
+			return depth
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+func unquote(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
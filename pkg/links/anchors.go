@@ -0,0 +1,136 @@
+package links
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Anchors are the places inside a note that a link can point to.
+type Anchors struct {
+	Headings []string `json:"headings"`
+	// Blocks are the ids of lines ending in ^id, without the caret.
+	Blocks []string `json:"blocks"`
+}
+
+// headingPattern matches ATX headings, without their closing hashes.
+var headingPattern = regexp.MustCompile(`^ {0,3}#{1,6}[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+var blockPattern = regexp.MustCompile(`(?:^|\s)\^([A-Za-z0-9-]+)[ \t]*$`)
+
+// ParseAnchors returns the headings and block ids of a note, skipping code and
+// frontmatter.
+func ParseAnchors(content string) Anchors {
+	anchors := Anchors{Headings: []string{}, Blocks: []string{}}
+	lines := strings.Split(content, "\n")
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	fence := ""
+	for _, line := range lines[start:] {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			anchors.Headings = append(anchors.Headings, match[1])
+		}
+		if match := blockPattern.FindStringSubmatch(line); match != nil {
+			anchors.Blocks = append(anchors.Blocks, match[1])
+		}
+	}
+	return anchors
+}
+
+// HasHeading reports whether heading names one of the headings, either by its
+// text, in any case, or by its slug as in a markdown fragment. Nested wiki-link
+// headings (A#B) are matched by the last one.
+func (a Anchors) HasHeading(heading string) bool {
+	wanted := Slug(lastHeading(heading))
+	seen := map[string]int{}
+	for _, h := range a.Headings {
+		slug := Slug(h)
+		if slug == wanted {
+			return true
+		}
+		// Repeated headings get numbered slugs: intro, intro-1, intro-2
+		if n := seen[slug]; n > 0 && wanted == slug+"-"+strconv.Itoa(n) {
+			return true
+		}
+		seen[slug]++
+	}
+	return false
+}
+
+func (a Anchors) HasBlock(id string) bool {
+	return slices.Contains(a.Blocks, id)
+}
+
+// SuggestHeading returns the one heading that heading most likely meant, if
+// there is exactly one: ignoring punctuation, one contains the other.
+func (a Anchors) SuggestHeading(heading string) (string, bool) {
+	wanted := Loose(lastHeading(heading))
+	if wanted == "" {
+		return "", false
+	}
+	found := []string{}
+	for _, h := range a.Headings {
+		have := Loose(h)
+		if have != "" && (strings.Contains(have, wanted) || strings.Contains(wanted, have)) {
+			found = append(found, h)
+		}
+	}
+	if len(found) != 1 {
+		return "", false
+	}
+	return found[0], true
+}
+
+// Slug turns a heading into a markdown fragment the way GitHub does: lower
+// case, punctuation dropped and spaces replaced by dashes.
+func Slug(heading string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			slug.WriteRune(r)
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+	return slug.String()
+}
+
+// Loose reduces a name to its lower-case letters and digits, to find files or
+// headings that differ only in case, spacing or punctuation.
+func Loose(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func lastHeading(heading string) string {
+	return heading[strings.LastIndex(heading, "#")+1:]
+}
@@ -0,0 +1,135 @@
+package links
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Files is the set of files of a notespace that links are resolved against.
+// Paths are relative to the root and slash-separated. It is not safe for
+// concurrent use.
+type Files struct {
+	files map[string]struct{}
+	names map[string][]string // lower-case name, with and without note extension -> rels
+}
+
+func NewFiles(rels ...string) *Files {
+	f := &Files{files: map[string]struct{}{}, names: map[string][]string{}}
+	for _, rel := range rels {
+		f.Add(rel)
+	}
+	return f
+}
+
+func (f *Files) Add(rel string) {
+	if _, ok := f.files[rel]; ok {
+		return
+	}
+	f.files[rel] = struct{}{}
+	for _, name := range names(rel) {
+		f.names[name] = append(f.names[name], rel)
+	}
+}
+
+func (f *Files) Remove(rel string) {
+	if _, ok := f.files[rel]; !ok {
+		return
+	}
+	delete(f.files, rel)
+	for _, name := range names(rel) {
+		f.names[name] = slices.DeleteFunc(f.names[name], func(other string) bool { return other == rel })
+		if len(f.names[name]) == 0 {
+			delete(f.names, name)
+		}
+	}
+}
+
+func (f *Files) Has(rel string) bool {
+	_, ok := f.files[rel]
+	return ok
+}
+
+// Named returns the files with the given name, ignoring case. Notes are also
+// found by their name without extension.
+func (f *Files) Named(name string) []string {
+	return slices.Clone(f.names[strings.ToLower(name)])
+}
+
+// Resolve returns the file link, written in the note source, points to, or ""
+// if it is missing.
+func (f *Files) Resolve(source string, link Link) string {
+	switch {
+	case link.Kind == KindWiki && link.Rel == "":
+		return f.Find(source, WikiName(link.Target))
+	case link.Rel == "" || f.Has(link.Rel):
+		return link.Rel
+	}
+	// Some tools leave out the extension of markdown links too
+	for _, ext := range NOTE_EXTENSIONS {
+		if f.Has(link.Rel + ext) {
+			return link.Rel + ext
+		}
+	}
+	return ""
+}
+
+// Find returns the file named by a wiki link written in source. A name with a
+// folder must match the end of the path; among several files, the one closest
+// to source wins.
+func (f *Files) Find(source string, name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ToLower(name)), "/")
+	if name == "" || name == "." {
+		return ""
+	}
+
+	candidates := []string{}
+	for _, rel := range f.names[path.Base(name)] {
+		lower := strings.ToLower(rel)
+		stem := strings.TrimSuffix(lower, path.Ext(lower))
+		if !strings.Contains(name, "/") || lower == name || stem == name || strings.HasSuffix(lower, "/"+name) || strings.HasSuffix(stem, "/"+name) {
+			candidates = append(candidates, rel)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	dir := path.Dir(source)
+	slices.SortFunc(candidates, func(a, b string) int {
+		if near := closeness(dir, b) - closeness(dir, a); near != 0 {
+			return near
+		}
+		if depth := strings.Count(a, "/") - strings.Count(b, "/"); depth != 0 {
+			return depth
+		}
+		return strings.Compare(a, b)
+	})
+	return candidates[0]
+}
+
+// closeness is the number of leading folders rel shares with dir.
+func closeness(dir string, rel string) int {
+	if dir == "." {
+		return 0
+	}
+	a, b := strings.Split(dir, "/"), strings.Split(path.Dir(rel), "/")
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// names returns the keys a file is found by in wiki links.
+func names(rel string) []string {
+	name := strings.ToLower(path.Base(rel))
+	if isNote(rel) {
+		return []string{name, strings.TrimSuffix(name, path.Ext(name))}
+	}
+	return []string{name}
+}
+
+func isNote(rel string) bool {
+	return slices.Contains(NOTE_EXTENSIONS, strings.ToLower(path.Ext(rel)))
+}
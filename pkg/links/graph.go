@@ -0,0 +1,306 @@
+package links
+
+import (
+	"context"
+	"noted/pkg/tags"
+	"path"
+	"slices"
+	"strings"
+)
+
+// GRAPH_LIMIT is the most notes a graph holds unless asked for more. Larger
+// notespaces keep their best connected notes.
+const GRAPH_LIMIT = 2000
+
+// GRAPH_DEPTH is how many links away from the focus note a graph reaches by
+// default.
+const GRAPH_DEPTH = 2
+
+// TAG_EDGE_LIMIT skips tag edges for tags on more notes than this: a tag on
+// every note says little about how they connect and would add an edge for each
+// pair.
+const TAG_EDGE_LIMIT = 50
+
+type EdgeKind string
+
+const (
+	EdgeLink  EdgeKind = "link"
+	EdgeEmbed EdgeKind = "embed"
+	// EdgeTag joins two notes that share tags.
+	EdgeTag EdgeKind = "tag"
+)
+
+type GraphOptions struct {
+	// Folder keeps the notes below this absolute path.
+	Folder string `json:"folder"`
+	// Tags keeps the notes with any of these tags or tags nested below them.
+	Tags []string `json:"tags"`
+	// Focus is the absolute path of a note to center the graph on. Only notes
+	// within Depth links of it, in either direction, are kept.
+	Focus string `json:"focus"`
+	// Depth defaults to GRAPH_DEPTH.
+	Depth int `json:"depth"`
+	// TagEdges adds edges between notes that share tags.
+	TagEdges bool `json:"tagEdges"`
+	// Limit defaults to GRAPH_LIMIT.
+	Limit int `json:"limit"`
+}
+
+// Node is one note of the graph.
+type Node struct {
+	// ID is the path relative to the root, slash-separated.
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	// Title is the frontmatter title, or the file name without extension.
+	Title string `json:"title"`
+	// Folder is relative to the root and empty for notes at the root.
+	Folder string   `json:"folder"`
+	Tags   []string `json:"tags"`
+	// Degree is the number of link and embed edges of the node in this graph.
+	Degree int `json:"degree"`
+}
+
+// Edge joins two nodes by ID. Link and embed edges point from the note with the
+// link to the linked note; tag edges go from the lower to the higher ID.
+type Edge struct {
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Kind   EdgeKind `json:"kind"`
+	// Weight counts the links, or the shared tags, behind the edge.
+	Weight int `json:"weight"`
+	// Tags are the shared tags of a tag edge.
+	Tags []string `json:"tags,omitempty"`
+}
+
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+	// Truncated is set when notes were left out to stay within the limit.
+	Truncated bool `json:"truncated"`
+}
+
+type edgeKey struct {
+	source, target string
+	kind           EdgeKind
+}
+
+// Graph returns the notes matching opts and the links between them. Only
+// titles, tags and paths are returned, never note contents.
+func (x *Index) Graph(ctx context.Context, opts GraphOptions) (Graph, error) {
+	if err := x.wait(ctx); err != nil {
+		return Graph{}, err
+	}
+	if opts.Depth <= 0 {
+		opts.Depth = GRAPH_DEPTH
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = GRAPH_LIMIT
+	}
+	// Nothing outside the notespace is in the graph, so a folder or focus
+	// there selects no notes rather than being ignored
+	empty := Graph{Nodes: []Node{}, Edges: []Edge{}}
+	folder, focus := "", ""
+	if opts.Folder != "" {
+		rel, ok := x.rel(opts.Folder)
+		if !ok {
+			return empty, nil
+		}
+		if rel != "." {
+			folder = rel
+		}
+	}
+	if opts.Focus != "" {
+		rel, ok := x.rel(opts.Focus)
+		if !ok {
+			return empty, nil
+		}
+		focus = rel
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if focus != "" {
+		if _, ok := x.notes[focus]; !ok {
+			return empty, nil
+		}
+	}
+
+	// The focus note is kept even when it does not match the filters
+	keep := map[string]bool{}
+	for rel, n := range x.notes {
+		if rel == focus || x.matches(rel, n, folder, opts.Tags) {
+			keep[rel] = true
+		}
+	}
+
+	weights := map[edgeKey]int{}
+	checked := 0
+	for source := range keep {
+		if checked++; checked%1024 == 0 && ctx.Err() != nil {
+			return Graph{}, ctx.Err()
+		}
+		for _, link := range x.notes[source].links {
+			target := x.files.Resolve(source, link)
+			if target == source || !keep[target] {
+				continue
+			}
+			kind := EdgeLink
+			if link.Embed {
+				kind = EdgeEmbed
+			}
+			weights[edgeKey{source, target, kind}]++
+		}
+	}
+
+	degrees := map[string]int{}
+	for key := range weights {
+		degrees[key.source]++
+		degrees[key.target]++
+	}
+
+	var order []string
+	if focus != "" {
+		order = nearby(focus, opts.Depth, weights)
+	} else {
+		order = make([]string, 0, len(keep))
+		for rel := range keep {
+			order = append(order, rel)
+		}
+		slices.SortFunc(order, func(a, b string) int {
+			if d := degrees[b] - degrees[a]; d != 0 {
+				return d
+			}
+			return strings.Compare(a, b)
+		})
+	}
+
+	graph := Graph{Nodes: []Node{}, Edges: []Edge{}}
+	if len(order) > opts.Limit {
+		order, graph.Truncated = order[:opts.Limit], true
+	}
+	slices.Sort(order)
+
+	included := map[string]bool{}
+	for _, rel := range order {
+		included[rel] = true
+	}
+	for key, weight := range weights {
+		if included[key.source] && included[key.target] {
+			graph.Edges = append(graph.Edges, Edge{Source: key.source, Target: key.target, Kind: key.kind, Weight: weight})
+		}
+	}
+	degrees = map[string]int{}
+	for _, edge := range graph.Edges {
+		degrees[edge.Source]++
+		degrees[edge.Target]++
+	}
+	if opts.TagEdges {
+		graph.Edges = append(graph.Edges, x.tagEdgesLocked(order)...)
+	}
+	slices.SortFunc(graph.Edges, func(a, b Edge) int {
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Target, b.Target); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Kind), string(b.Kind))
+	})
+
+	for _, rel := range order {
+		n := x.notes[rel]
+		folder := path.Dir(rel)
+		if folder == "." {
+			folder = ""
+		}
+		graph.Nodes = append(graph.Nodes, Node{
+			ID:     rel,
+			Path:   x.abs(rel),
+			Title:  n.displayTitle(rel),
+			Folder: folder,
+			Tags:   slices.Clone(n.tags),
+			Degree: degrees[rel],
+		})
+	}
+	return graph, nil
+}
+
+// matches reports whether the note rel is below folder and has one of want.
+func (x *Index) matches(rel string, n *note, folder string, want []string) bool {
+	if folder != "" && !strings.HasPrefix(rel, folder+"/") {
+		return false
+	}
+	if len(want) == 0 {
+		return true
+	}
+	for _, tag := range n.tags {
+		for _, w := range want {
+			if tags.Match(tag, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagEdgesLocked joins the notes in rels that share tags. The caller must hold
+// x.mu.
+func (x *Index) tagEdgesLocked(rels []string) []Edge {
+	tagged := map[string][]string{} // lower-case tag -> rels, in order
+	for _, rel := range rels {
+		for _, tag := range x.notes[rel].tags {
+			tag = strings.ToLower(tag)
+			tagged[tag] = append(tagged[tag], rel)
+		}
+	}
+
+	shared := map[[2]string][]string{}
+	for tag, notes := range tagged {
+		if len(notes) > TAG_EDGE_LIMIT {
+			continue
+		}
+		for i, a := range notes {
+			for _, b := range notes[i+1:] {
+				pair := [2]string{a, b}
+				shared[pair] = append(shared[pair], tag)
+			}
+		}
+	}
+
+	edges := make([]Edge, 0, len(shared))
+	for pair, sharedTags := range shared {
+		slices.Sort(sharedTags)
+		edges = append(edges, Edge{Source: pair[0], Target: pair[1], Kind: EdgeTag, Weight: len(sharedTags), Tags: sharedTags})
+	}
+	return edges
+}
+
+// nearby returns the notes within depth edges of focus, following edges both
+// ways, nearest first.
+func nearby(focus string, depth int, weights map[edgeKey]int) []string {
+	neighbours := map[string][]string{}
+	for key := range weights {
+		neighbours[key.source] = append(neighbours[key.source], key.target)
+		neighbours[key.target] = append(neighbours[key.target], key.source)
+	}
+
+	order := []string{focus}
+	seen := map[string]bool{focus: true}
+	level := []string{focus}
+	for d := 0; d < depth && len(level) > 0; d++ {
+		next := []string{}
+		for _, rel := range level {
+			for _, other := range neighbours[rel] {
+				if !seen[other] {
+					seen[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		slices.Sort(next)
+		order = append(order, next...)
+		level = next
+	}
+	return order
+}
@@ -0,0 +1,400 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"noted/pkg/frontmatter"
+	"noted/pkg/tags"
+	"noted/pkg/watch"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NOTE_EXTENSIONS are the files whose links are parsed. A wiki link may leave
+// them out: [[Note]] finds Note.md.
+var NOTE_EXTENSIONS = []string{".md", ".markdown", ".mdx"}
+
+// MAX_FILE_SIZE keeps generated or pasted dumps from being parsed.
+const MAX_FILE_SIZE = 2 << 20
+
+// note holds the parsed links, title and tags of one file.
+type note struct {
+	modified time.Time
+	size     int64
+	links    []Link
+	title    string // from the frontmatter, if any
+	tags     []string
+	data     map[string]any // frontmatter, empty if missing or invalid
+}
+
+// displayTitle returns the title of the note at rel, falling back to its file
+// name without extension.
+func (n *note) displayTitle(rel string) string {
+	if n.title != "" {
+		return n.title
+	}
+	return strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+}
+
+// Metadata describes one note for queries, without its contents.
+type Metadata struct {
+	Path string
+	Rel  string
+	// Title is the frontmatter title, or the file name without extension.
+	Title    string
+	Tags     []string
+	Data     map[string]any
+	Modified time.Time
+	Size     int64
+}
+
+// Index knows every file below one notespace root, the links between its notes
+// and their titles and tags. It is built once in the background and then kept up to date file by
+// file. Wiki links are resolved when asked for, so they follow files as they
+// are created, renamed and removed.
+type Index struct {
+	root  string
+	prune []string
+
+	mu    sync.RWMutex
+	files *Files
+	notes map[string]*note // rel -> parsed links
+
+	ready  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New returns an empty index of root that skips directories named in prune.
+// Fill it by passing every file to Add, then call Finish.
+func New(root string, prune []string) *Index {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Index{
+		root:   root,
+		prune:  prune,
+		files:  NewFiles(),
+		notes:  map[string]*note{},
+		ready:  make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (x *Index) Root() string {
+	return x.root
+}
+
+// Add lists the file at path and parses it if it is a note, as found by a walk
+// of the notespace shared with other indexes. It fails once the index is
+// closed, so the walk can stop.
+func (x *Index) Add(path string, info fs.FileInfo) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	if !watch.Pruned(x.root, path, x.prune) {
+		x.add(path, info)
+	}
+	return nil
+}
+
+// Finish ends the build started at start by adding every file with Add.
+// Queries wait until it is called.
+func (x *Index) Finish(start time.Time) {
+	defer close(x.ready)
+
+	if x.ctx.Err() != nil {
+		return
+	}
+
+	x.mu.RLock()
+	count := 0
+	for _, n := range x.notes {
+		count += len(n.links)
+	}
+	x.mu.RUnlock()
+	log.Printf("Found %d links in %s in %v", count, x.root, time.Since(start).Round(time.Millisecond))
+}
+
+// Close stops a running build. The index must not be used afterwards.
+func (x *Index) Close() {
+	x.cancel()
+}
+
+// Update reparses path after it was created, written or removed. Directories
+// are added recursively; unchanged notes are skipped.
+func (x *Index) Update(path string) {
+	if watch.Pruned(x.root, path, x.prune) {
+		return
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		x.Remove(path)
+		return
+	}
+	if info.IsDir() {
+		x.addTree(path)
+		return
+	}
+	x.add(path, info)
+}
+
+// Remove drops path and everything below it.
+func (x *Index) Remove(path string) {
+	rel, ok := x.rel(path)
+	if !ok {
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for file := range x.files.files {
+		if file == rel || strings.HasPrefix(file, rel+"/") || rel == "." {
+			x.files.Remove(file)
+			delete(x.notes, file)
+		}
+	}
+}
+
+// Apply updates the index from a batch of watcher events.
+func (x *Index) Apply(events []watch.Event) {
+	for _, event := range events {
+		if x.ctx.Err() != nil {
+			return
+		}
+		if event.Op == watch.Remove {
+			x.Remove(event.Path)
+		} else {
+			x.Update(event.Path)
+		}
+	}
+}
+
+// Outgoing returns the links of the note at path in the order they appear.
+// Links whose target is missing have no Path.
+func (x *Index) Outgoing(ctx context.Context, path string) ([]Link, error) {
+	if err := x.wait(ctx); err != nil {
+		return nil, err
+	}
+	// A path outside the notespace has no links
+	rel, ok := x.rel(path)
+	if !ok {
+		return []Link{}, nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	links := []Link{}
+	if n, ok := x.notes[rel]; ok {
+		for _, link := range n.links {
+			links = append(links, x.resolveLocked(rel, link))
+		}
+	}
+	return links, nil
+}
+
+// Backlinks returns the links from other notes, and from the note itself, to
+// the file at path, ordered by note and position.
+func (x *Index) Backlinks(ctx context.Context, path string) ([]Link, error) {
+	if err := x.wait(ctx); err != nil {
+		return nil, err
+	}
+	// Nothing links to a path outside the notespace. Its rel is empty, like
+	// that of every link to a missing file.
+	rel, ok := x.rel(path)
+	if !ok {
+		return []Link{}, nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	links := []Link{}
+	checked := 0
+	for source, n := range x.notes {
+		if checked++; checked%1024 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for _, link := range n.links {
+			if link = x.resolveLocked(source, link); link.Rel == rel {
+				links = append(links, link)
+			}
+		}
+	}
+	slices.SortFunc(links, func(a, b Link) int {
+		if a.Source != b.Source {
+			return strings.Compare(a.Source, b.Source)
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return links, nil
+}
+
+// NoteTags returns the tags of every note, by absolute path. Notes without tags
+// have an empty list, so that queries such as NOT draft can select them.
+func (x *Index) NoteTags(ctx context.Context) (map[string][]string, error) {
+	if err := x.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	notes := make(map[string][]string, len(x.notes))
+	for rel, n := range x.notes {
+		notes[x.abs(rel)] = slices.Clone(n.tags)
+	}
+	return notes, nil
+}
+
+// Metadata returns the titles, tags, frontmatter and file details of every
+// note, sorted by path. The maps are shared and must not be modified.
+func (x *Index) Metadata(ctx context.Context) ([]Metadata, error) {
+	if err := x.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	notes := make([]Metadata, 0, len(x.notes))
+	for rel, n := range x.notes {
+		notes = append(notes, Metadata{
+			Path:     x.abs(rel),
+			Rel:      rel,
+			Title:    n.displayTitle(rel),
+			Tags:     slices.Clone(n.tags),
+			Data:     n.data,
+			Modified: n.modified,
+			Size:     n.size,
+		})
+	}
+	slices.SortFunc(notes, func(a, b Metadata) int {
+		return strings.Compare(a.Rel, b.Rel)
+	})
+	return notes, nil
+}
+
+func (x *Index) wait(ctx context.Context) error {
+	select {
+	case <-x.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resolveLocked fills in Rel, Path and Source of a link written in source.
+// The caller must hold x.mu.
+func (x *Index) resolveLocked(source string, link Link) Link {
+	link.Source = x.abs(source)
+	link.Rel = x.files.Resolve(source, link)
+	if link.Rel != "" {
+		link.Path = x.abs(link.Rel)
+	}
+	return link
+}
+
+func (x *Index) addTree(dir string) {
+	err := watch.Walk(dir, x.prune, func(path string, info fs.FileInfo) error {
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+		x.add(path, info)
+		return nil
+	})
+	if err != nil && x.ctx.Err() == nil {
+		log.Printf("Failed to index links of %s: %v", dir, err)
+	}
+}
+
+// add lists the file at path and parses its links if it is a note that changed
+// since it was last parsed.
+func (x *Index) add(path string, info fs.FileInfo) {
+	rel, ok := x.rel(path)
+	if !ok {
+		return
+	}
+	if !info.Mode().IsRegular() {
+		x.Remove(path)
+		return
+	}
+	if !isNote(rel) || info.Size() > MAX_FILE_SIZE {
+		x.mu.Lock()
+		x.files.Add(rel)
+		delete(x.notes, rel)
+		x.mu.Unlock()
+		return
+	}
+
+	x.mu.RLock()
+	n, ok := x.notes[rel]
+	x.mu.RUnlock()
+	if ok && n.size == info.Size() && !info.ModTime().After(n.modified) {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		x.Remove(path)
+		return
+	}
+	content := string(data)
+	matter, err := frontmatter.Parse(content)
+	if err != nil {
+		matter.Data = map[string]any{}
+	}
+	n = &note{
+		modified: info.ModTime(),
+		size:     info.Size(),
+		links:    Parse(rel, content),
+		title:    frontmatterTitle(matter.Data),
+		tags:     tags.ParseWith(content, matter.Data),
+		data:     matter.Data,
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	// A newer version was parsed while this one was being read
+	if current, ok := x.notes[rel]; ok && current.modified.After(n.modified) {
+		return
+	}
+	x.files.Add(rel)
+	x.notes[rel] = n
+}
+
+// frontmatterTitle returns the `title` key of parsed frontmatter, or "" if it
+// has none.
+func frontmatterTitle(data map[string]any) string {
+	switch title := data["title"].(type) {
+	case nil, map[string]any, []any:
+		return ""
+	case string:
+		return strings.TrimSpace(title)
+	default:
+		return fmt.Sprint(title)
+	}
+}
+
+// rel returns path relative to the root, slash-separated.
+func (x *Index) rel(path string) (string, bool) {
+	rel, err := filepath.Rel(x.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func (x *Index) abs(rel string) string {
+	return filepath.Join(x.root, filepath.FromSlash(rel))
+}
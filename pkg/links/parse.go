@@ -0,0 +1,187 @@
+package links
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+type Kind string
+
+const (
+	// KindMarkdown is an inline link or image: [text](target "title").
+	KindMarkdown Kind = "markdown"
+	// KindWiki is a [[target#heading|alias]] link, or ![[target]] embed.
+	KindWiki Kind = "wiki"
+)
+
+// Link is a link from one note to a file of the same notespace.
+type Link struct {
+	Kind Kind `json:"kind"`
+	// Embed is set for images and ![[embeds]].
+	Embed bool `json:"embed"`
+	// Line and Column are 1-based; Column counts characters.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Text is the link text or wiki-link alias.
+	Text string `json:"text"`
+	// Target is the link destination as written.
+	Target  string `json:"target"`
+	Heading string `json:"heading,omitempty"`
+	// Block is the id of a ^block anchor, without the caret.
+	Block string `json:"block,omitempty"`
+	// Rel is the linked file relative to the root, slash-separated. Markdown
+	// links are resolved by Parse; wiki links need the file list and are
+	// resolved by the Index. Empty when the target is not found.
+	Rel string `json:"rel"`
+	// Path and Source are the absolute paths of the linked file and of the note
+	// containing the link. They are set by the Index.
+	Path   string `json:"path"`
+	Source string `json:"source"`
+}
+
+// markdownPattern matches inline markdown links and images: [text](target "title").
+var markdownPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*(<[^>]*>|[^)\s]+)(?:\s+"[^"]*")?\s*\)`)
+
+// wikiPattern matches [[target#heading|alias]] and ![[embeds]].
+var wikiPattern = regexp.MustCompile(`(!?)\[\[([^\[\]\n]+)\]\]`)
+
+// schemePattern matches targets outside the notespace, e.g. https: or mailto:.
+var schemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+var codeSpanPattern = regexp.MustCompile("`[^`]*`")
+
+// Parse returns the links of note, which is relative to the root, to files of
+// the notespace. Links in code and links to other sites are skipped.
+func Parse(note string, content string) []Link {
+	links := []Link{}
+	fence := ""
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		// Blank out code spans, keeping the offsets of everything else
+		line = codeSpanPattern.ReplaceAllStringFunc(strings.TrimSuffix(line, "\r"), func(span string) string {
+			return strings.Repeat(" ", len(span))
+		})
+		column := func(offset int) int {
+			return utf8.RuneCountInString(line[:offset]) + 1
+		}
+
+		for _, match := range markdownPattern.FindAllStringSubmatchIndex(line, -1) {
+			target := strings.TrimSuffix(strings.TrimPrefix(line[match[6]:match[7]], "<"), ">")
+			rel, ok := ResolveTarget(note, target)
+			if !ok {
+				continue
+			}
+			link := Link{
+				Kind:   KindMarkdown,
+				Embed:  match[3] > match[2],
+				Line:   i + 1,
+				Column: column(match[0]),
+				Text:   line[match[4]:match[5]],
+				Target: target,
+				Rel:    rel,
+			}
+			if _, fragment, ok := strings.Cut(target, "#"); ok {
+				if unescaped, err := url.PathUnescape(fragment); err == nil {
+					fragment = unescaped
+				}
+				link.Heading, link.Block = anchor(fragment)
+			}
+			links = append(links, link)
+		}
+
+		for _, match := range wikiPattern.FindAllStringSubmatchIndex(line, -1) {
+			inner := line[match[4]:match[5]]
+			// Inside tables the alias separator is escaped
+			inner = strings.ReplaceAll(inner, `\|`, "|")
+			target, alias, _ := strings.Cut(inner, "|")
+			name, fragment, _ := strings.Cut(target, "#")
+			target = strings.TrimSpace(target)
+
+			link := Link{
+				Kind:   KindWiki,
+				Embed:  match[3] > match[2],
+				Line:   i + 1,
+				Column: column(match[0]),
+				Text:   strings.TrimSpace(alias),
+				Target: target,
+			}
+			link.Heading, link.Block = anchor(strings.TrimSpace(fragment))
+			if strings.TrimSpace(name) == "" {
+				// [[#Heading]] points into the note itself
+				link.Rel = note
+			}
+			if link.Text == "" {
+				link.Text = target
+			}
+			links = append(links, link)
+		}
+	}
+	slices.SortStableFunc(links, func(a, b Link) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return links
+}
+
+// anchor splits a link fragment into a heading or a ^block id.
+func anchor(fragment string) (heading string, block string) {
+	if id, ok := strings.CutPrefix(fragment, "^"); ok {
+		return "", id
+	}
+	return fragment, ""
+}
+
+// WikiName returns the part of a wiki-link target that names the file.
+func WikiName(target string) string {
+	name, _, _ := strings.Cut(target, "#")
+	return strings.TrimSpace(name)
+}
+
+// ResolveTarget returns a markdown link target relative to the root, or false if
+// it does not point to a file in the notespace. An anchor-only target points to
+// note itself.
+func ResolveTarget(note string, target string) (string, bool) {
+	if target == "" || schemePattern.MatchString(target) {
+		return "", false
+	}
+	if strings.HasPrefix(target, "#") {
+		return note, true
+	}
+
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if target == "" {
+		return "", false
+	}
+
+	var resolved string
+	if strings.HasPrefix(target, "/") {
+		resolved = path.Clean(strings.TrimPrefix(target, "/"))
+	} else {
+		resolved = path.Join(path.Dir(note), target)
+	}
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", false
+	}
+	return resolved, true
+}
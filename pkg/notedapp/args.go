@@ -0,0 +1,55 @@
+package notedapp
+
+import (
+	"flag"
+	"io"
+	"strings"
+)
+
+// Args is what the app was asked to open from the command line.
+type Args struct {
+	// Paths are notespace folders or notes inside a notespace.
+	Paths     []string
+	NewWindow bool
+	ReadOnly  bool
+}
+
+// ParseArgs reads `noted [-new-window] [-read-only] [path ...]`. Flags may also
+// follow the paths, as in `noted ~/notes -read-only`; everything after `--` is
+// a path.
+func ParseArgs(args []string) (Args, error) {
+	parsed := Args{}
+
+	flags := flag.NewFlagSet("noted", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&parsed.NewWindow, "new-window", false, "open a new window even if the notespace is already open")
+	flags.BoolVar(&parsed.ReadOnly, "read-only", false, "open without allowing changes")
+
+	// macOS adds a process serial number when the app is started from Finder
+	filtered := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-psn_") {
+			filtered = append(filtered, arg)
+		}
+	}
+
+	parsed.Paths = []string{}
+	rest := filtered
+	for len(rest) > 0 {
+		if err := flags.Parse(rest); err != nil {
+			return Args{}, err
+		}
+		// Parse stops at the first path, or after a `--`
+		remaining := flags.Args()
+		if consumed := len(rest) - len(remaining); consumed > 0 && rest[consumed-1] == "--" {
+			parsed.Paths = append(parsed.Paths, remaining...)
+			break
+		}
+		if len(remaining) == 0 {
+			break
+		}
+		parsed.Paths = append(parsed.Paths, remaining[0])
+		rest = remaining[1:]
+	}
+	return parsed, nil
+}
@@ -0,0 +1,49 @@
+package notedapp
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// UNIQUE_ID identifies Noted to the single-instance lock shared by all launches.
+const UNIQUE_ID = "com.maw1a.noted"
+
+// secondInstance handles a launch forwarded by another process. The paths it
+// was given are opened here; without any, the app is brought to the front.
+func (app *App) secondInstance(data application.SecondInstanceData) {
+	var args Args
+	if len(data.Args) > 1 {
+		parsed, err := ParseArgs(data.Args[1:])
+		if err != nil {
+			log.Printf("Ignoring arguments of second instance %v: %v", data.Args, err)
+		}
+		args = parsed
+	}
+
+	// Relative paths are relative to where the second instance was started
+	for i, path := range args.Paths {
+		if !filepath.IsAbs(path) {
+			args.Paths[i] = filepath.Join(data.WorkingDir, path)
+		}
+	}
+
+	if len(args.Paths) > 0 && app.open(args) {
+		return
+	}
+	app.focus()
+}
+
+// focus brings a window of the app to the front, reopening the picker if there is none.
+func (app *App) focus() {
+	if window := app.Window.Current(); window != nil {
+		window.Focus()
+		return
+	}
+	if windows := app.Window.GetAll(); len(windows) > 0 {
+		windows[len(windows)-1].Focus()
+		return
+	}
+	openPicker(app.App, app.settings)
+}
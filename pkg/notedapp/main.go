@@ -2,37 +2,90 @@ package notedapp
 
 import (
 	"io/fs"
+	"log"
+	"noted/pkg/doctor"
 	"noted/pkg/editor"
 	"noted/pkg/file"
 	"noted/pkg/router"
-	"noted/pkg/ui"
+	"noted/pkg/settings"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 type App struct {
 	*application.App
+
+	editor   application.Service
+	settings *settings.Store
 }
 
 func New(name string, description string, assets fs.FS) App {
+	store := settings.New()
+	editorService := editor.Service(store)
+	fileService := file.Service(store.GetSettings().Preferences.SortMode, editor.WriteGuard(editorService), editor.SaveHook(editorService))
+	store.SortModeChanged = file.SortModeSetter(fileService)
+
+	var noted App
 	app := application.New(application.Options{
 		Name:        name,
 		Description: description,
-		Services: []application.Service{editor.Service(), file.Service()},
+		Services: []application.Service{
+			editorService,
+			fileService,
+			settings.Service(store),
+			doctor.Service(editor.WriteGuard(editorService)),
+		},
 		Assets: router.AssetOptions(assets),
+		// Later launches hand their arguments to this process and exit
+		SingleInstance: &application.SingleInstanceOptions{
+			UniqueID: UNIQUE_ID,
+			OnSecondInstanceLaunch: func(data application.SecondInstanceData) {
+				noted.secondInstance(data)
+			},
+		},
 		Mac: application.MacOptions{
 			ApplicationShouldTerminateAfterLastWindowClosed: true,
 		},
 	})
-	return App {
+	noted = App {
 		app,
+		editorService,
+		store,
 	}
+	return noted
 }
 
-func (app *App)Run() error {
-	ui.OpenDirectoryWindow(app.App)
+// Run opens the paths given in args, or otherwise the previous session, and
+// falls back to the directory picker when no window could be opened.
+func (app *App) Run(args Args) error {
+	var opened bool
+	if len(args.Paths) > 0 {
+		opened = app.open(args)
+	} else {
+		opened = editor.Restore(app.editor)
+	}
+	if !opened {
+		openPicker(app.App, app.settings)
+	}
 
 	err := app.App.Run()
 
 	return err
 }
+
+// open opens every path in args and reports whether any window was opened.
+func (app *App) open(args Args) bool {
+	opened := false
+	for _, path := range args.Paths {
+		inspection, err := editor.Open(app.editor, path, editor.OpenOptions{
+			NewWindow: args.NewWindow,
+			ReadOnly:  args.ReadOnly,
+		})
+		if err != nil {
+			log.Printf("Failed to open %s: %v", path, err)
+			continue
+		}
+		opened = opened || inspection.Opened
+	}
+	return opened
+}
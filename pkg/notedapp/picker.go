@@ -0,0 +1,54 @@
+package notedapp
+
+import (
+	"log"
+	"noted/pkg/settings"
+	"noted/pkg/ui"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// pickerSaveDelay batches the move events of a drag into one settings write.
+const pickerSaveDelay = 500 * time.Millisecond
+
+// openPicker opens the directory picker where it was last placed and remembers
+// where the user moves it.
+func openPicker(app *application.App, store *settings.Store) *application.WebviewWindow {
+	saved := store.GetPickerBounds()
+	// A picker saved at the top-left of the screen is at 0,0 too, so whether a
+	// position was saved is told by its size
+	var position *application.Point
+	if !saved.IsZero() {
+		position = &application.Point{X: saved.X, Y: saved.Y}
+	}
+	window := ui.OpenDirectoryWindow(app, position)
+	if position != nil {
+		ui.ClampToScreen(window, saved.Screen)
+	}
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	window.OnWindowEvent(events.Common.WindowDidMove, func(*application.WindowEvent) {
+		rect := window.Bounds()
+		bounds := settings.Bounds{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height}
+		if screen, err := window.GetScreen(); err == nil && screen != nil {
+			bounds.Screen = screen.ID
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(pickerSaveDelay, func() {
+			if err := store.SavePickerBounds(bounds); err != nil {
+				log.Printf("Failed to save picker position: %v", err)
+			}
+		})
+	})
+
+	return window
+}
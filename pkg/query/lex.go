@@ -0,0 +1,161 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenTag
+	tokenDuration
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos and end are byte offsets into the query.
+	pos, end int
+	number   float64
+	duration duration
+}
+
+// UNITS are the duration suffixes of numbers, as in 7d or 2 weeks.
+var UNITS = map[string]duration{
+	"s": {clock: 1e9}, "sec": {clock: 1e9}, "second": {clock: 1e9}, "seconds": {clock: 1e9},
+	"min": {clock: 60e9}, "minute": {clock: 60e9}, "minutes": {clock: 60e9},
+	"h": {clock: 3600e9}, "hr": {clock: 3600e9}, "hour": {clock: 3600e9}, "hours": {clock: 3600e9},
+	"d": {days: 1}, "day": {days: 1}, "days": {days: 1},
+	"w": {days: 7}, "wk": {days: 7}, "week": {days: 7}, "weeks": {days: 7},
+	"mo": {months: 1}, "month": {months: 1}, "months": {months: 1},
+	"y": {months: 12}, "yr": {months: 12}, "year": {months: 12}, "years": {months: 12},
+}
+
+var operators = []string{"&&", "||", "!=", "<=", ">=", "==", "=", "<", ">", "(", ")", ",", "+", "-", "*", "/", "!"}
+
+// lex splits a query into tokens, ending with a tokenEOF.
+func lex(source string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(source); {
+		r, size := utf8.DecodeRuneInString(source[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case r == '"' || r == '\'':
+			var text strings.Builder
+			i += size
+			for {
+				if i >= len(source) {
+					return nil, fmt.Errorf("unterminated string at %d", start+1)
+				}
+				c := source[i]
+				if c == '\\' && i+1 < len(source) {
+					text.WriteByte(source[i+1])
+					i += 2
+					continue
+				}
+				i++
+				if rune(c) == r {
+					break
+				}
+				text.WriteByte(c)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), pos: start, end: i})
+
+		case r == '#':
+			i += size
+			for i < len(source) {
+				r, size := utf8.DecodeRuneInString(source[i:])
+				if !isTagRune(r) {
+					break
+				}
+				i += size
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("empty tag at %d", start+1)
+			}
+			tokens = append(tokens, token{kind: tokenTag, text: source[start+1 : i], pos: start, end: i})
+
+		case unicode.IsDigit(r):
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			number, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad number %q at %d", source[start:i], start+1)
+			}
+
+			// A unit turns the number into a duration: 7d, 3 days
+			j := i
+			for j < len(source) && source[j] == ' ' {
+				j++
+			}
+			k := j
+			for k < len(source) && isLetter(source[k]) {
+				k++
+			}
+			if unit, ok := UNITS[strings.ToLower(source[j:k])]; ok && (j == i || len(source[j:k]) > 2) {
+				tokens = append(tokens, token{kind: tokenDuration, text: source[start:k], pos: start, end: k, duration: unit.times(number)})
+				i = k
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start, end: i, number: number})
+
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(source) {
+				r, size := utf8.DecodeRuneInString(source[i:])
+				// A dash inside a name belongs to it: due-date. Subtraction needs spaces.
+				if r == '-' && i+1 < len(source) {
+					next, _ := utf8.DecodeRuneInString(source[i+1:])
+					if unicode.IsLetter(next) {
+						i += size
+						continue
+					}
+				}
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start, end: i})
+
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", r, start+1)
+			}
+			i += len(op)
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start, end: i})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source), end: len(source)}), nil
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '/'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
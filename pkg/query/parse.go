@@ -0,0 +1,450 @@
+package query
+
+import (
+	"fmt"
+	"noted/pkg/status"
+	"slices"
+	"strings"
+)
+
+// Query is a parsed query over the notes of a notespace:
+//
+//	TABLE status, due AS "Due date", file.mtime
+//	FROM #project AND "work" -#archived
+//	WHERE status != "done" AND due < date(today) + 7d
+//	SORT due ASC, file.name
+//	GROUP BY status
+//	LIMIT 50
+//
+// TABLE lists expressions as columns after the note itself; LIST shows the note
+// and at most one expression. The other clauses are optional, may come in any
+// order and are applied as FROM, WHERE, SORT, LIMIT and GROUP BY.
+//
+// FROM selects notes by #tag, including nested tags, and by "folder" or "file"
+// path relative to the root, combined with AND, OR, NOT or -. Expressions read
+// frontmatter fields by name, nested ones with dots, and file.name, file.path,
+// file.folder, file.ext, file.title, file.tags, file.mtime and file.size.
+// Frontmatter strings holding a date are dates; numbers with a unit (7d, 2
+// weeks, 1mo) are durations that can be added to dates. A dash inside a name
+// belongs to it (due-date), so subtraction needs spaces.
+type Query struct {
+	list    bool
+	columns []column
+	from    source
+	where   expr
+	sort    []sortKey
+	group   expr
+	limit   int
+}
+
+type column struct {
+	expr expr
+	name string
+}
+
+type sortKey struct {
+	expr expr
+	desc bool
+}
+
+// KEYWORDS start clauses or modify them, so they cannot be used as field names.
+var KEYWORDS = []string{"TABLE", "LIST", "FROM", "WHERE", "SORT", "GROUP", "BY", "LIMIT", "AS", "ASC", "DESC", "AND", "OR", "NOT"}
+
+// FUNCTIONS maps each function to the number of arguments it takes.
+var FUNCTIONS = map[string]int{
+	"date":       1,
+	"contains":   2,
+	"startswith": 2,
+	"endswith":   2,
+	"lower":      1,
+	"upper":      1,
+	"length":     1,
+	"default":    2,
+}
+
+// Parse parses a query.
+func Parse(source string) (*Query, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, status.New(status.FAILED_QUERY, "", err)
+	}
+	p := &parser{source: source, tokens: tokens}
+	q, err := p.query()
+	if err != nil {
+		return nil, status.New(status.FAILED_QUERY, "", err)
+	}
+	return q, nil
+}
+
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether the next token is one of words, ignoring case.
+func (p *parser) keyword(words ...string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && slices.Contains(words, strings.ToUpper(t.text))
+}
+
+func (p *parser) op(ops ...string) bool {
+	t := p.peek()
+	return t.kind == tokenOp && slices.Contains(ops, t.text)
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	found := "end of query"
+	if t.kind != tokenEOF {
+		found = fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Errorf("%s, found %s at %d", fmt.Sprintf(format, args...), found, t.pos+1)
+}
+
+func (p *parser) query() (*Query, error) {
+	q := &Query{}
+	switch {
+	case p.keyword("TABLE"):
+		p.next()
+		for !p.clause() {
+			if len(q.columns) > 0 {
+				if !p.op(",") {
+					return nil, p.errorf("expected , or a clause")
+				}
+				p.next()
+			}
+			col, err := p.column()
+			if err != nil {
+				return nil, err
+			}
+			q.columns = append(q.columns, col)
+		}
+	case p.keyword("LIST"):
+		p.next()
+		q.list = true
+		if !p.clause() {
+			col, err := p.column()
+			if err != nil {
+				return nil, err
+			}
+			q.columns = append(q.columns, col)
+		}
+	default:
+		return nil, p.errorf("expected TABLE or LIST")
+	}
+
+	seen := map[string]bool{}
+	for p.peek().kind != tokenEOF {
+		if !p.clause() {
+			return nil, p.errorf("expected FROM, WHERE, SORT, GROUP BY or LIMIT")
+		}
+		name := strings.ToUpper(p.next().text)
+		if seen[name] {
+			return nil, fmt.Errorf("%s given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FROM":
+			q.from, err = p.sourceOr()
+		case "WHERE":
+			q.where, err = p.or()
+		case "SORT":
+			q.sort, err = p.sortKeys()
+		case "GROUP":
+			if !p.keyword("BY") {
+				return nil, p.errorf("expected BY")
+			}
+			p.next()
+			q.group, err = p.or()
+		case "LIMIT":
+			t := p.peek()
+			if t.kind != tokenNumber || t.number < 1 || t.number != float64(int(t.number)) {
+				return nil, p.errorf("expected a whole number")
+			}
+			p.next()
+			q.limit = int(t.number)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// clause reports whether the next token starts a clause or ends the query.
+func (p *parser) clause() bool {
+	return p.peek().kind == tokenEOF || p.keyword("FROM", "WHERE", "SORT", "GROUP", "LIMIT")
+}
+
+func (p *parser) column() (column, error) {
+	start := p.peek().pos
+	e, err := p.or()
+	if err != nil {
+		return column{}, err
+	}
+	name := strings.TrimSpace(p.source[start:p.tokens[p.pos-1].end])
+	if p.keyword("AS") {
+		p.next()
+		t := p.peek()
+		if t.kind != tokenString && t.kind != tokenIdent {
+			return column{}, p.errorf("expected a column name")
+		}
+		p.next()
+		name = t.text
+	}
+	return column{expr: e, name: name}, nil
+}
+
+func (p *parser) sortKeys() ([]sortKey, error) {
+	keys := []sortKey{}
+	for {
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		key := sortKey{expr: e}
+		if p.keyword("ASC", "DESC") {
+			key.desc = strings.ToUpper(p.next().text) == "DESC"
+		}
+		keys = append(keys, key)
+		if !p.op(",") {
+			return keys, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) or() (expr, error) {
+	left, err := p.and()
+	for err == nil && (p.keyword("OR") || p.op("||")) {
+		p.next()
+		var right expr
+		if right, err = p.and(); err == nil {
+			left = orExpr{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) and() (expr, error) {
+	left, err := p.not()
+	for err == nil && (p.keyword("AND") || p.op("&&")) {
+		p.next()
+		var right expr
+		if right, err = p.not(); err == nil {
+			left = andExpr{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) not() (expr, error) {
+	if p.keyword("NOT") || p.op("!") {
+		p.next()
+		e, err := p.not()
+		return notExpr{e}, err
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expr, error) {
+	left, err := p.additive()
+	if err != nil || !p.op("=", "==", "!=", "<", "<=", ">", ">=") {
+		return left, err
+	}
+	op := p.next().text
+	right, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+	return compareExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) additive() (expr, error) {
+	left, err := p.multiplicative()
+	for err == nil && p.op("+", "-") {
+		op := p.next().text
+		var right expr
+		if right, err = p.multiplicative(); err == nil {
+			left = arithmeticExpr{op: op, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) multiplicative() (expr, error) {
+	left, err := p.unary()
+	for err == nil && p.op("*", "/") {
+		op := p.next().text
+		var right expr
+		if right, err = p.unary(); err == nil {
+			left = arithmeticExpr{op: op, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.op("-") {
+		p.next()
+		e, err := p.unary()
+		return negExpr{e}, err
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		return literal{t.number}, nil
+	case tokenString:
+		p.next()
+		return literal{t.text}, nil
+	case tokenDuration:
+		p.next()
+		return literal{t.duration}, nil
+	case tokenOp:
+		if t.text != "(" {
+			break
+		}
+		p.next()
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.op(")") {
+			return nil, p.errorf("expected )")
+		}
+		p.next()
+		return e, nil
+	case tokenIdent:
+		upper := strings.ToUpper(t.text)
+		switch {
+		case upper == "TRUE" || upper == "FALSE":
+			p.next()
+			return literal{upper == "TRUE"}, nil
+		case upper == "NULL":
+			p.next()
+			return literal{nil}, nil
+		case slices.Contains(KEYWORDS, upper):
+			return nil, p.errorf("expected a value")
+		}
+		p.next()
+		if p.op("(") {
+			return p.call(t)
+		}
+		return field(strings.Split(t.text, ".")), nil
+	}
+	return nil, p.errorf("expected a value")
+}
+
+func (p *parser) call(name token) (expr, error) {
+	function := strings.ToLower(name.text)
+	arity, ok := FUNCTIONS[function]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at %d", name.text, name.pos+1)
+	}
+	p.next() // (
+
+	args := []expr{}
+	for !p.op(")") {
+		if len(args) > 0 {
+			if !p.op(",") {
+				return nil, p.errorf("expected , or )")
+			}
+			p.next()
+		}
+		// date(today) names a day, not a field
+		if t := p.peek(); function == "date" && t.kind == tokenIdent && relativeDay(t.text) {
+			p.next()
+			args = append(args, literal{strings.ToLower(t.text)})
+			continue
+		}
+		arg, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next() // )
+
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d at %d", function, arity, len(args), name.pos+1)
+	}
+	return callExpr{name: function, args: args}, nil
+}
+
+// sourceOr parses the notes FROM selects.
+func (p *parser) sourceOr() (source, error) {
+	left, err := p.sourceAnd()
+	for err == nil && p.keyword("OR") {
+		p.next()
+		var right source
+		if right, err = p.sourceAnd(); err == nil {
+			left = orSource{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) sourceAnd() (source, error) {
+	left, err := p.sourceNot()
+	for err == nil && !p.clause() && !p.keyword("OR") && !p.op(")") {
+		// Sources next to each other must all match
+		if p.keyword("AND") {
+			p.next()
+		}
+		var right source
+		if right, err = p.sourceNot(); err == nil {
+			left = andSource{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) sourceNot() (source, error) {
+	if p.keyword("NOT") || p.op("-", "!") {
+		p.next()
+		s, err := p.sourceNot()
+		return notSource{s}, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokenTag:
+		p.next()
+		return tagSource(t.text), nil
+	case t.kind == tokenString:
+		p.next()
+		return pathSource(strings.Trim(t.text, "/")), nil
+	case t.kind == tokenOp && t.text == "(":
+		p.next()
+		s, err := p.sourceOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.op(")") {
+			return nil, p.errorf("expected )")
+		}
+		p.next()
+		return s, nil
+	}
+	return nil, p.errorf("expected a #tag or \"folder\"")
+}
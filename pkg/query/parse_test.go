@@ -0,0 +1,114 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{"due-date", []string{"ident due-date"}},
+		{"a - b", []string{"ident a", "op -", "ident b"}},
+		{"a -b", []string{"ident a", "op -", "ident b"}},
+		{"a-1", []string{"ident a", "op -", "number 1"}},
+		{"file.name", []string{"ident file.name"}},
+		{"7d", []string{"duration 7d"}},
+		{"7 days", []string{"duration 7 days"}},
+		{"7days", []string{"duration 7days"}},
+		{"1.5h", []string{"duration 1.5h"}},
+		{"2 mo", []string{"number 2", "ident mo"}},
+		{"7 d", []string{"number 7", "ident d"}},
+		{"7 dogs", []string{"number 7", "ident dogs"}},
+		{"date(today) + 7d", []string{"ident date", "op (", "ident today", "op )", "op +", "duration 7d"}},
+		{`"a \"b\"" 'c'`, []string{`string a "b"`, "string c"}},
+		{"#project/noted -#archived", []string{"tag project/noted", "op -", "tag archived"}},
+		{"a<=b", []string{"ident a", "op <=", "ident b"}},
+	}
+
+	kinds := map[tokenKind]string{
+		tokenIdent:    "ident",
+		tokenNumber:   "number",
+		tokenString:   "string",
+		tokenTag:      "tag",
+		tokenDuration: "duration",
+		tokenOp:       "op",
+	}
+	for _, test := range tests {
+		tokens, err := lex(test.source)
+		if err != nil {
+			t.Errorf("lex(%q): %v", test.source, err)
+			continue
+		}
+		got := []string{}
+		for _, token := range tokens[:len(tokens)-1] {
+			got = append(got, fmt.Sprintf("%s %s", kinds[token.kind], token.text))
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("lex(%q) = %q, want %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"", "expected TABLE or LIST, found end of query at 1"},
+		{"SELECT a", `expected TABLE or LIST, found "SELECT" at 1`},
+		{"TABLE a b", `expected , or a clause, found "b" at 9`},
+		{"LIST a, b", `expected FROM, WHERE, SORT, GROUP BY or LIMIT, found "," at 7`},
+		{"TABLE a FROM", `expected a #tag or "folder", found end of query at 13`},
+		{"TABLE a FROM (#x", "expected ), found end of query at 17"},
+		{"TABLE a WHERE (b = 1", "expected ), found end of query at 21"},
+		{"TABLE a WHERE", "expected a value, found end of query at 14"},
+		{"TABLE AND", `expected a value, found "AND" at 7`},
+		{"TABLE a GROUP status", `expected BY, found "status" at 15`},
+		{"TABLE a LIMIT 0", `expected a whole number, found "0" at 15`},
+		{"TABLE a LIMIT 1.5", `expected a whole number, found "1.5" at 15`},
+		{"TABLE a LIMIT 2 LIMIT 3", "LIMIT given twice"},
+		{"TABLE nope(a)", "unknown function nope at 7"},
+		{"TABLE lower(a, b)", "lower takes 1 arguments, got 2 at 7"},
+		{"TABLE a AS", `expected a column name, found end of query at 11`},
+		{"TABLE a WHERE b = 'x", "unterminated string at 19"},
+		{"TABLE a FROM #", "empty tag at 14"},
+		{"TABLE a WHERE b ? c", `unexpected '?' at 17`},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.source)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want %q", test.source, test.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Parse(%q) = %q, want %q", test.source, err.Error(), test.want)
+		}
+	}
+}
+
+func TestParseClauses(t *testing.T) {
+	q, err := Parse(`table status, due-date AS "Due", file.name limit 5 SORT due-date DESC, file.name FROM #a -"archive" GROUP BY status WHERE due-date - 1d < date(today)`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	names := []string{}
+	for _, col := range q.columns {
+		names = append(names, col.name)
+	}
+	if want := []string{"status", "Due", "file.name"}; !slices.Equal(names, want) {
+		t.Errorf("columns = %q, want %q", names, want)
+	}
+	if len(q.sort) != 2 || !q.sort[0].desc || q.sort[1].desc {
+		t.Errorf("sort = %+v, want due-date DESC, file.name", q.sort)
+	}
+	if q.limit != 5 || q.from == nil || q.where == nil || q.group == nil {
+		t.Errorf("query = %+v, want every clause set", q)
+	}
+}
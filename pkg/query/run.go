@@ -0,0 +1,402 @@
+package query
+
+import (
+	"context"
+	"noted/pkg/links"
+	"noted/pkg/tags"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Result is a query rendered as a table.
+type Result struct {
+	// Columns name the cells of each row; the first is always the note.
+	Columns []string `json:"columns"`
+	// Groups hold the rows in order. A query without GROUP BY has one group
+	// with an empty key.
+	Groups []Group `json:"groups"`
+	// Total counts the notes that matched, before LIMIT.
+	Total int `json:"total"`
+}
+
+type Group struct {
+	Key  string `json:"key"`
+	Rows []Row  `json:"rows"`
+}
+
+type Row struct {
+	Path  string `json:"path"`
+	Title string `json:"title"`
+	// Cells are the values of the columns after the note, as text.
+	Cells []string `json:"cells"`
+}
+
+// env is what expressions are evaluated against: one note and the time the
+// query runs at.
+type env struct {
+	note *links.Metadata
+	now  time.Time
+}
+
+type row struct {
+	note  *links.Metadata
+	sort  []any
+	group any
+}
+
+// Run evaluates q over notes.
+func Run(ctx context.Context, q *Query, notes []links.Metadata, now time.Time) (Result, error) {
+	rows := []row{}
+	for i := range notes {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return Result{}, ctx.Err()
+		}
+		e := &env{note: &notes[i], now: now}
+		if q.from != nil && !q.from.match(e.note) {
+			continue
+		}
+		if q.where != nil && !truthy(q.where.eval(e)) {
+			continue
+		}
+
+		r := row{note: e.note}
+		for _, key := range q.sort {
+			r.sort = append(r.sort, key.expr.eval(e))
+		}
+		if q.group != nil {
+			r.group = q.group.eval(e)
+		}
+		rows = append(rows, r)
+	}
+
+	slices.SortStableFunc(rows, func(a, b row) int {
+		for i, key := range q.sort {
+			x, y := a.sort[i], b.sort[i]
+			// Notes without the value go last either way
+			if (x == nil) != (y == nil) {
+				if x == nil {
+					return 1
+				}
+				return -1
+			}
+			c := order(x, y)
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	result := Result{Columns: []string{"File"}, Groups: []Group{}, Total: len(rows)}
+	for _, col := range q.columns {
+		result.Columns = append(result.Columns, col.name)
+	}
+	if q.limit > 0 && len(rows) > q.limit {
+		rows = rows[:q.limit]
+	}
+
+	keys := []any{}
+	groups := map[string]*Group{}
+	for _, r := range rows {
+		e := &env{note: r.note, now: now}
+		out := Row{Path: r.note.Path, Title: r.note.Title, Cells: []string{}}
+		for _, col := range q.columns {
+			out.Cells = append(out.Cells, display(col.expr.eval(e)))
+		}
+
+		key := display(r.group)
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{Key: key, Rows: []Row{}}
+			groups[key] = g
+			keys = append(keys, r.group)
+		}
+		g.Rows = append(g.Rows, out)
+	}
+	slices.SortStableFunc(keys, order)
+	for _, key := range keys {
+		result.Groups = append(result.Groups, *groups[display(key)])
+	}
+	return result, nil
+}
+
+type expr interface {
+	eval(e *env) any
+}
+
+type literal struct{ value any }
+
+type field []string
+
+type notExpr struct{ expr }
+
+type negExpr struct{ expr }
+
+type andExpr struct{ left, right expr }
+
+type orExpr struct{ left, right expr }
+
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+type arithmeticExpr struct {
+	op          string
+	left, right expr
+}
+
+type callExpr struct {
+	name string
+	args []expr
+}
+
+func (l literal) eval(*env) any {
+	return l.value
+}
+
+func (f field) eval(e *env) any {
+	if f[0] == "file" && len(f) == 2 {
+		return e.fileField(f[1])
+	}
+
+	value := lookup(e.note.Data, f[0])
+	for _, name := range f[1:] {
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = lookup(nested, name)
+	}
+	return normalize(value)
+}
+
+// lookup returns the value of key, or of a key that differs only in case.
+func lookup(data map[string]any, key string) any {
+	if value, ok := data[key]; ok {
+		return value
+	}
+	for k, value := range data {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return nil
+}
+
+func (e *env) fileField(name string) any {
+	n := e.note
+	switch strings.ToLower(name) {
+	case "name":
+		return strings.TrimSuffix(path.Base(n.Rel), path.Ext(n.Rel))
+	case "path":
+		return n.Rel
+	case "folder":
+		if folder := path.Dir(n.Rel); folder != "." {
+			return folder
+		}
+		return ""
+	case "ext":
+		return strings.TrimPrefix(path.Ext(n.Rel), ".")
+	case "title":
+		return n.Title
+	case "tags":
+		found := make([]any, len(n.Tags))
+		for i, tag := range n.Tags {
+			found[i] = tag
+		}
+		return found
+	case "mtime":
+		return n.Modified
+	case "size":
+		return float64(n.Size)
+	}
+	return nil
+}
+
+func (n notExpr) eval(e *env) any {
+	return !truthy(n.expr.eval(e))
+}
+
+func (n negExpr) eval(e *env) any {
+	switch v := n.expr.eval(e).(type) {
+	case float64:
+		return -v
+	case duration:
+		return v.neg()
+	}
+	return nil
+}
+
+func (a andExpr) eval(e *env) any {
+	return truthy(a.left.eval(e)) && truthy(a.right.eval(e))
+}
+
+func (o orExpr) eval(e *env) any {
+	return truthy(o.left.eval(e)) || truthy(o.right.eval(e))
+}
+
+func (c compareExpr) eval(e *env) any {
+	left, right := c.left.eval(e), c.right.eval(e)
+	switch c.op {
+	case "=", "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+	n, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	}
+	return n >= 0
+}
+
+func (a arithmeticExpr) eval(e *env) any {
+	return arithmetic(a.op, a.left.eval(e), a.right.eval(e))
+}
+
+// eval runs a function. Text functions ignore case.
+func (c callExpr) eval(e *env) any {
+	args := make([]any, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(e)
+	}
+
+	switch c.name {
+	case "date":
+		return toDate(args[0], e.now)
+	case "contains":
+		switch haystack := args[0].(type) {
+		case []any:
+			return slices.ContainsFunc(haystack, func(item any) bool {
+				a, ok1 := item.(string)
+				b, ok2 := args[1].(string)
+				if ok1 && ok2 {
+					return strings.EqualFold(a, b)
+				}
+				return equal(item, args[1])
+			})
+		case string:
+			return strings.Contains(strings.ToLower(haystack), strings.ToLower(display(args[1])))
+		case map[string]any:
+			return lookup(haystack, display(args[1])) != nil
+		}
+		return false
+	case "startswith":
+		s, ok := args[0].(string)
+		return ok && strings.HasPrefix(strings.ToLower(s), strings.ToLower(display(args[1])))
+	case "endswith":
+		s, ok := args[0].(string)
+		return ok && strings.HasSuffix(strings.ToLower(s), strings.ToLower(display(args[1])))
+	case "lower":
+		if s, ok := args[0].(string); ok {
+			return strings.ToLower(s)
+		}
+	case "upper":
+		if s, ok := args[0].(string); ok {
+			return strings.ToUpper(s)
+		}
+	case "length":
+		switch v := args[0].(type) {
+		case string:
+			return float64(utf8.RuneCountInString(v))
+		case []any:
+			return float64(len(v))
+		case map[string]any:
+			return float64(len(v))
+		}
+		return 0.0
+	case "default":
+		if args[0] == nil {
+			return args[1]
+		}
+		return args[0]
+	}
+	return nil
+}
+
+// relativeDay reports whether name is a day date() understands by name.
+func relativeDay(name string) bool {
+	switch strings.ToLower(name) {
+	case "today", "now", "yesterday", "tomorrow":
+		return true
+	}
+	return false
+}
+
+func toDate(value any, now time.Time) any {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		switch strings.ToLower(v) {
+		case "now":
+			return now
+		case "today":
+			return today
+		case "yesterday":
+			return today.AddDate(0, 0, -1)
+		case "tomorrow":
+			return today.AddDate(0, 0, 1)
+		}
+		if t, ok := parseDate(v); ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// source is the part of a FROM clause that selects notes.
+type source interface {
+	match(n *links.Metadata) bool
+}
+
+type tagSource string
+
+// pathSource is a folder or file path relative to the root; empty is the root.
+type pathSource string
+
+type notSource struct{ source }
+
+type andSource struct{ left, right source }
+
+type orSource struct{ left, right source }
+
+func (t tagSource) match(n *links.Metadata) bool {
+	return slices.ContainsFunc(n.Tags, func(tag string) bool {
+		return tags.Match(tag, string(t))
+	})
+}
+
+func (p pathSource) match(n *links.Metadata) bool {
+	folder := string(p)
+	return folder == "" || n.Rel == folder || strings.HasPrefix(n.Rel, folder+"/") ||
+		strings.TrimSuffix(n.Rel, path.Ext(n.Rel)) == folder
+}
+
+func (s notSource) match(n *links.Metadata) bool {
+	return !s.source.match(n)
+}
+
+func (s andSource) match(n *links.Metadata) bool {
+	return s.left.match(n) && s.right.match(n)
+}
+
+func (s orSource) match(n *links.Metadata) bool {
+	return s.left.match(n) || s.right.match(n)
+}
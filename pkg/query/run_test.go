@@ -0,0 +1,120 @@
+package query
+
+import (
+	"context"
+	"noted/pkg/links"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	notes := []links.Metadata{
+		{Path: "/n/a.md", Rel: "a.md", Title: "A", Tags: []string{"project"}, Data: map[string]any{"status": "done", "rank": 3, "due-date": "2024-05-01"}},
+		{Path: "/n/b.md", Rel: "b.md", Title: "B", Tags: []string{"project/noted"}, Data: map[string]any{"status": "open", "rank": 1}},
+		{Path: "/n/c.md", Rel: "c.md", Title: "C", Data: map[string]any{"status": "open", "rank": 2, "due-date": "2024-05-10"}},
+		{Path: "/n/work/d.md", Rel: "work/d.md", Title: "D", Tags: []string{"projects"}, Data: map[string]any{"status": "done"}},
+		{Path: "/n/work/e.md", Rel: "work/e.md", Title: "E", Data: map[string]any{"rank": 5}},
+	}
+	now := time.Date(2024, 5, 5, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		source  string
+		columns []string
+		groups  []string
+		rows    [][]string
+		total   int
+	}{
+		{
+			name:    "sort ascending puts missing values last",
+			source:  "TABLE rank SORT rank",
+			columns: []string{"File", "rank"},
+			groups:  []string{""},
+			rows:    [][]string{{"b.md", "1"}, {"c.md", "2"}, {"a.md", "3"}, {"work/e.md", "5"}, {"work/d.md", ""}},
+			total:   5,
+		},
+		{
+			name:    "sort descending puts missing values last",
+			source:  "TABLE rank SORT rank DESC",
+			columns: []string{"File", "rank"},
+			groups:  []string{""},
+			rows:    [][]string{{"work/e.md", "5"}, {"a.md", "3"}, {"c.md", "2"}, {"b.md", "1"}, {"work/d.md", ""}},
+			total:   5,
+		},
+		{
+			name:    "limit applies before group by",
+			source:  "LIST SORT rank DESC LIMIT 3 GROUP BY status",
+			columns: []string{"File"},
+			groups:  []string{"done", "open", ""},
+			rows:    [][]string{{"a.md"}, {"c.md"}, {"work/e.md"}},
+			total:   5,
+		},
+		{
+			name:    "dash in a name",
+			source:  "TABLE due-date WHERE due-date SORT file.name",
+			columns: []string{"File", "due-date"},
+			groups:  []string{""},
+			rows:    [][]string{{"a.md", "2024-05-01"}, {"c.md", "2024-05-10"}},
+			total:   2,
+		},
+		{
+			name:    "dash as minus",
+			source:  "TABLE rank - 1 AS less WHERE rank - 1 > 1 SORT rank",
+			columns: []string{"File", "less"},
+			groups:  []string{""},
+			rows:    [][]string{{"a.md", "2"}, {"work/e.md", "4"}},
+			total:   2,
+		},
+		{
+			name:    "date arithmetic with units",
+			source:  "TABLE due-date + 7d, due-date + 1 week WHERE due-date < date(today) + 7 days",
+			columns: []string{"File", "due-date + 7d", "due-date + 1 week"},
+			groups:  []string{""},
+			rows:    [][]string{{"a.md", "2024-05-08", "2024-05-08"}, {"c.md", "2024-05-17", "2024-05-17"}},
+			total:   2,
+		},
+		{
+			name:    "from tag and folder",
+			source:  `LIST FROM #project OR "work" AND -#projects`,
+			columns: []string{"File"},
+			groups:  []string{""},
+			rows:    [][]string{{"a.md"}, {"b.md"}, {"work/e.md"}},
+			total:   3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := Parse(test.source)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			result, err := Run(context.Background(), q, notes, now)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			groups := []string{}
+			rows := [][]string{}
+			for _, group := range result.Groups {
+				groups = append(groups, group.Key)
+				for _, row := range group.Rows {
+					rows = append(rows, append([]string{row.Path[len("/n/"):]}, row.Cells...))
+				}
+			}
+			if !slices.Equal(result.Columns, test.columns) {
+				t.Errorf("Columns = %q, want %q", result.Columns, test.columns)
+			}
+			if !slices.Equal(groups, test.groups) {
+				t.Errorf("groups = %q, want %q", groups, test.groups)
+			}
+			if !slices.EqualFunc(rows, test.rows, slices.Equal) {
+				t.Errorf("rows = %q, want %q", rows, test.rows)
+			}
+			if result.Total != test.total {
+				t.Errorf("Total = %d, want %d", result.Total, test.total)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values are nil, bool, float64, string, time.Time, duration, []any or
+// map[string]any.
+
+// duration is a calendar span: months and days are added to dates as such, so
+// 1mo after January 31st is the end of February.
+type duration struct {
+	months int
+	days   int
+	clock  time.Duration
+}
+
+func (d duration) times(n float64) duration {
+	whole := math.Trunc(n)
+	return duration{
+		months: d.months * int(whole),
+		days:   d.days * int(whole),
+		clock:  time.Duration(float64(d.clock) * n),
+	}
+}
+
+// approx converts d to a plain duration, with 30-day months, for comparing.
+func (d duration) approx() time.Duration {
+	return time.Duration(d.months*30+d.days)*24*time.Hour + d.clock
+}
+
+func (d duration) neg() duration {
+	return duration{months: -d.months, days: -d.days, clock: -d.clock}
+}
+
+func (d duration) String() string {
+	parts := []string{}
+	if d.months/12 != 0 {
+		parts = append(parts, strconv.Itoa(d.months/12)+"y")
+	}
+	if d.months%12 != 0 {
+		parts = append(parts, strconv.Itoa(d.months%12)+"mo")
+	}
+	days, clock := d.days+int(d.clock/(24*time.Hour)), d.clock%(24*time.Hour)
+	if days != 0 {
+		parts = append(parts, strconv.Itoa(days)+"d")
+	}
+	if clock != 0 {
+		// 1h30m0s reads better as 1h30m
+		text := clock.String()
+		if strings.HasSuffix(text, "m0s") {
+			text = strings.TrimSuffix(text, "0s")
+		}
+		if strings.HasSuffix(text, "h0m") {
+			text = strings.TrimSuffix(text, "0m")
+		}
+		parts = append(parts, text)
+	}
+	if len(parts) == 0 {
+		return "0s"
+	}
+	return strings.Join(parts, " ")
+}
+
+// datePattern matches the dates and times that frontmatter strings are read as.
+var datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?)?$`)
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04Z07:00",
+}
+
+// parseDate reads a date or date and time; dates without a zone are local.
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if !datePattern.MatchString(s) {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalize converts a decoded frontmatter value to a query value: numbers
+// become float64 and strings that hold a date become time.Time.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case nil, bool, float64, time.Time, map[string]any:
+		return v
+	case string:
+		if t, ok := parseDate(v); ok {
+			return t
+		}
+		return v
+	case []any:
+		normalized := make([]any, len(v))
+		for i, item := range v {
+			normalized[i] = normalize(item)
+		}
+		return normalized
+	case fmt.Stringer:
+		// TOML dates and times
+		return normalize(v.String())
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	case reflect.Slice:
+		normalized := make([]any, rv.Len())
+		for i := range normalized {
+			normalized[i] = normalize(rv.Index(i).Interface())
+		}
+		return normalized
+	}
+	return fmt.Sprint(value)
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	case time.Time:
+		return !v.IsZero()
+	case duration:
+		return v != (duration{})
+	}
+	return true
+}
+
+// compare orders a and b if they are of comparable kinds. A string is compared
+// with a date as a date.
+func compare(a any, b any) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			return cmpFloat(x, y), true
+		}
+	case string:
+		switch y := b.(type) {
+		case string:
+			return strings.Compare(x, y), true
+		case time.Time:
+			if t, ok := parseDate(x); ok {
+				return t.Compare(y), true
+			}
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return cmpBool(x, y), true
+		}
+	case time.Time:
+		switch y := b.(type) {
+		case time.Time:
+			return x.Compare(y), true
+		case string:
+			if t, ok := parseDate(y); ok {
+				return x.Compare(t), true
+			}
+		}
+	case duration:
+		if y, ok := b.(duration); ok {
+			return cmpFloat(float64(x.approx()), float64(y.approx())), true
+		}
+	}
+	return 0, false
+}
+
+func equal(a any, b any) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if x, ok := a.([]any); ok {
+		y, ok := b.([]any)
+		return ok && slices.EqualFunc(x, y, equal)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// order is a total order of values for sorting: values of one kind by compare,
+// kinds by rank, and nil last.
+func order(a any, b any) int {
+	if c, ok := compare(a, b); ok {
+		return c
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	return strings.Compare(display(a), display(b))
+}
+
+func rank(value any) int {
+	switch value.(type) {
+	case bool:
+		return 0
+	case float64:
+		return 1
+	case duration:
+		return 2
+	case time.Time:
+		return 3
+	case string:
+		return 4
+	case []any:
+		return 5
+	case map[string]any:
+		return 6
+	case nil:
+		return 8
+	}
+	return 7
+}
+
+// arithmetic applies + - * / to numbers, dates and durations; + also joins
+// strings. Anything else gives nil.
+func arithmetic(op string, a any, b any) any {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch op {
+			case "+":
+				return x + y
+			case "-":
+				return x - y
+			case "*":
+				return x * y
+			case "/":
+				if y == 0 {
+					return nil
+				}
+				return x / y
+			}
+		}
+		if y, ok := b.(duration); ok && op == "*" {
+			return y.times(x)
+		}
+	case time.Time:
+		switch y := b.(type) {
+		case duration:
+			if op == "-" {
+				y = y.neg()
+			} else if op != "+" {
+				return nil
+			}
+			return x.AddDate(0, y.months, y.days).Add(y.clock)
+		case time.Time:
+			if op == "-" {
+				return duration{clock: x.Sub(y)}
+			}
+		}
+	case duration:
+		switch y := b.(type) {
+		case duration:
+			switch op {
+			case "+":
+				return duration{months: x.months + y.months, days: x.days + y.days, clock: x.clock + y.clock}
+			case "-":
+				return duration{months: x.months - y.months, days: x.days - y.days, clock: x.clock - y.clock}
+			}
+		case time.Time:
+			if op == "+" {
+				return arithmetic(op, y, x)
+			}
+		case float64:
+			if op == "*" {
+				return x.times(y)
+			}
+		}
+	}
+	if op == "+" {
+		if x, ok := a.(string); ok {
+			return x + display(b)
+		}
+		if y, ok := b.(string); ok {
+			return display(a) + y
+		}
+	}
+	return nil
+}
+
+// display writes a value as text for a table cell.
+func display(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04")
+	case duration:
+		return v.String()
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = display(item)
+		}
+		return strings.Join(items, ", ")
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for i, key := range keys {
+			keys[i] = key + ": " + display(normalize(v[key]))
+		}
+		return strings.Join(keys, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+func cmpFloat(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func cmpBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
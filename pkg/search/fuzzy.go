@@ -0,0 +1,263 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scores of a fuzzy match. Every matched character scores, characters that
+// start a word or path segment score more, and skipped characters cost a little.
+const (
+	scoreMatch       = 16
+	scoreGap         = -1
+	bonusSegment     = 12 // after a slash
+	bonusBoundary    = 9  // after a space, dash, underscore or dot
+	bonusCamel       = 8  // an upper-case letter after a lower-case one
+	bonusConsecutive = 6
+	bonusCase        = 1 // the case matches exactly
+	// bonusName is added when every match is in the file name, not its folders.
+	bonusName = 24
+)
+
+// RECENT_BOOST is added to the score of the most recently opened file and
+// decreases for older ones.
+const RECENT_BOOST = 40
+
+// FIND_LIMIT caps the results of a find when the caller gives no limit.
+const FIND_LIMIT = 50
+
+// maxPattern keeps the matching cost bounded for pasted queries.
+const maxPattern = 64
+
+// FileMatch is a file whose path or title matched a query. Positions are the
+// matched characters of Rel, TitlePositions those of Title.
+type FileMatch struct {
+	Path           string `json:"path"`
+	Rel            string `json:"rel"`
+	Title          string `json:"title,omitempty"`
+	Score          int    `json:"score"`
+	Positions      []int  `json:"positions"`
+	TitlePositions []int  `json:"titlePositions"`
+	Recent         bool   `json:"recent"`
+}
+
+// Find matches query against the paths and titles of the files below the root,
+// best first. recent lists recently opened files, most recent first; they rank
+// higher, and an empty query returns them followed by all other files. Hidden
+// files are skipped.
+func (x *Index) Find(ctx context.Context, query string, recent []string, limit int) ([]FileMatch, error) {
+	select {
+	case <-x.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if limit <= 0 {
+		limit = FIND_LIMIT
+	}
+
+	boost := map[string]int{}
+	for i, path := range recent {
+		boost[path] = RECENT_BOOST * (len(recent) - i) / len(recent)
+	}
+
+	pattern := []rune(strings.Join(strings.Fields(query), " "))
+	pattern = pattern[:min(len(pattern), maxPattern)]
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	matches := []FileMatch{}
+	checked := 0
+	for path, e := range x.files {
+		if checked++; checked%1024 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if hidden(e.rel) {
+			continue
+		}
+
+		match := FileMatch{
+			Path:           path,
+			Rel:            e.rel,
+			Title:          e.title,
+			Positions:      []int{},
+			TitlePositions: []int{},
+		}
+		bonus, recentFile := boost[path]
+		match.Recent = recentFile
+
+		if len(pattern) == 0 {
+			match.Score = bonus
+			matches = append(matches, match)
+			continue
+		}
+
+		score, positions, ok := fuzzyMatch(pattern, e.rel, true)
+		if ok {
+			match.Score, match.Positions = score, positions
+		}
+		if e.title != "" {
+			if score, positions, titleOk := fuzzyMatch(pattern, e.title, false); titleOk && (!ok || score > match.Score) {
+				match.Score, match.TitlePositions, match.Positions = score, positions, []int{}
+				ok = true
+			}
+		}
+		if !ok {
+			continue
+		}
+		match.Score += bonus
+		matches = append(matches, match)
+	}
+
+	slices.SortFunc(matches, func(a, b FileMatch) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		if len(a.Rel) != len(b.Rel) {
+			return len(a.Rel) - len(b.Rel)
+		}
+		return strings.Compare(a.Rel, b.Rel)
+	})
+	return matches[:min(len(matches), limit)], nil
+}
+
+// fuzzyMatch finds the best alignment of pattern with text, where pattern
+// characters appear in order but not necessarily next to each other. It
+// returns the score and the matched character positions of text.
+func fuzzyMatch(pattern []rune, text string, isPath bool) (int, []int, bool) {
+	runes := []rune(text)
+	n, m := len(runes), len(pattern)
+	if m == 0 || m > n {
+		return 0, nil, false
+	}
+
+	lower := make([]rune, n)
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	lowerPattern := make([]rune, m)
+	for i, r := range pattern {
+		lowerPattern[i] = unicode.ToLower(r)
+	}
+
+	// Cheap check first: most candidates are not even a subsequence
+	first, j := -1, 0
+	for i := 0; i < n && j < m; i++ {
+		if lower[i] == lowerPattern[j] {
+			if j == 0 {
+				first = i
+			}
+			j++
+		}
+	}
+	if j < m {
+		return 0, nil, false
+	}
+
+	bonus := make([]int, n)
+	for i := range runes {
+		bonus[i] = charBonus(runes, i)
+	}
+
+	// score[i][k] is the best score of pattern[:i+1] with pattern[i] matched at text[k];
+	// from[i][k] is where pattern[i-1] was matched in that alignment.
+	const none = -1 << 30
+	score := make([][]int, m)
+	from := make([][]int, m)
+	for i := range m {
+		score[i] = make([]int, n)
+		from[i] = make([]int, n)
+		for k := range n {
+			score[i][k] = none
+		}
+	}
+
+	for k := first; k < n; k++ {
+		if lower[k] == lowerPattern[0] {
+			score[0][k] = scoreMatch + bonus[k] + caseBonus(runes[k], pattern[0])
+		}
+	}
+	for i := 1; i < m; i++ {
+		// best tracks max(score[i-1][p] - scoreGap*p) over p < k-1, so a gap
+		// of k-p-1 characters costs scoreGap each
+		best, bestAt := none, -1
+		for k := i; k < n; k++ {
+			if p := k - 2; p >= 0 && score[i-1][p] != none && score[i-1][p]-scoreGap*p > best {
+				best, bestAt = score[i-1][p]-scoreGap*p, p
+			}
+			if lower[k] != lowerPattern[i] {
+				continue
+			}
+
+			value := scoreMatch + bonus[k] + caseBonus(runes[k], pattern[i])
+			candidate, at := none, -1
+			if best != none {
+				candidate, at = best+scoreGap*(k-1)+value, bestAt
+			}
+			if previous := score[i-1][k-1]; previous != none && previous+value+bonusConsecutive >= candidate {
+				candidate, at = previous+value+bonusConsecutive, k-1
+			}
+			score[i][k], from[i][k] = candidate, at
+		}
+	}
+
+	end, total := -1, none
+	for k := range n {
+		if score[m-1][k] > total {
+			end, total = k, score[m-1][k]
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, m)
+	for i, k := m-1, end; i >= 0; i-- {
+		positions[i] = k
+		k = from[i][k]
+	}
+
+	if isPath {
+		name := strings.LastIndexByte(text, '/')
+		if name < 0 || utf8.RuneCountInString(text[:name]) < positions[0] {
+			total += bonusName
+		}
+	}
+	// Among equal matches prefer shorter texts
+	total -= n / 16
+	return total, positions, true
+}
+
+// charBonus rates runes[i] as the start of a word.
+func charBonus(runes []rune, i int) int {
+	if i == 0 {
+		return bonusSegment
+	}
+	previous, current := runes[i-1], runes[i]
+	switch {
+	case previous == '/' || previous == '\\':
+		return bonusSegment
+	case previous == ' ' || previous == '-' || previous == '_' || previous == '.':
+		return bonusBoundary
+	case unicode.IsLower(previous) && unicode.IsUpper(current):
+		return bonusCamel
+	case !unicode.IsLetter(previous) && !unicode.IsDigit(previous) && (unicode.IsLetter(current) || unicode.IsDigit(current)):
+		return bonusBoundary
+	}
+	return 0
+}
+
+func caseBonus(text rune, pattern rune) int {
+	if text == pattern {
+		return bonusCase
+	}
+	return 0
+}
+
+// hidden reports whether rel or one of its folders starts with a dot.
+func hidden(rel string) bool {
+	return strings.HasPrefix(rel, ".") || strings.Contains(rel, "/.")
+}
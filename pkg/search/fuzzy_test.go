@@ -0,0 +1,79 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		positions []int
+	}{
+		{"consecutive", "meet", "meeting-notes.md", []int{0, 1, 2, 3}},
+		{"word start over earlier letter", "mn", "meeting-notes.md", []int{0, 8}},
+		{"camel case", "fb", "fooBar-fab.md", []int{0, 3}},
+		{"segment starts", "nm", "notes/meeting.md", []int{0, 6}},
+		{"case is ignored", "READ", "docs/readme.md", []int{5, 6, 7, 8}},
+		{"runes, not bytes", "üb", "über/b.md", []int{0, 5}},
+		{"not a subsequence", "mx", "meeting.md", nil},
+		{"longer than text", "notes.md", "a.md", nil},
+		{"empty pattern", "", "a.md", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch([]rune(test.pattern), test.text, true)
+			if ok != (test.positions != nil) || !slices.Equal(positions, test.positions) {
+				t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v", test.pattern, test.text, positions, ok, test.positions)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	x := New("/notes", nil)
+	for _, e := range []entry{
+		{rel: "notes/a.md"},
+		{rel: "b/note.md"},
+		{rel: "journal/2024.md", title: "Notebook"},
+		{rel: ".noted/state.json"},
+		{rel: "docs/.hidden/note.md"},
+		{rel: "zzz.md"},
+	} {
+		x.files["/notes/"+e.rel] = e
+	}
+	x.Finish(time.Now())
+
+	tests := []struct {
+		name   string
+		query  string
+		recent []string
+		want   []string
+	}{
+		{"name before folder and title", "note", nil, []string{"b/note.md", "notes/a.md", "journal/2024.md"}},
+		{"hidden files are skipped", "state", nil, []string{}},
+		{"recent first on empty query", "", []string{"/notes/zzz.md", "/notes/notes/a.md"}, []string{"zzz.md", "notes/a.md", "b/note.md", "journal/2024.md"}},
+		{"recent ranks higher", "a", []string{"/notes/journal/2024.md"}, []string{"journal/2024.md", "notes/a.md"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches, err := x.Find(context.Background(), test.query, test.recent, 0)
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			got := []string{}
+			for _, match := range matches {
+				got = append(got, match.Rel)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Find(%q) = %q, want %q", test.query, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"log"
+	"noted/pkg/file"
+	"noted/pkg/watch"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EXTENSIONS are the files that are indexed; everything else is skipped.
+var EXTENSIONS = []string{".md", ".markdown", ".mdx", ".txt"}
+
+// MAX_FILE_SIZE keeps generated or pasted dumps out of the index.
+const MAX_FILE_SIZE = 2 << 20
+
+// document is an indexed file.
+type document struct {
+	modified time.Time
+	size     int64
+	length   int
+	// terms holds the positions of every stemmed term in the file.
+	terms map[string][]int
+	// words are the distinct words as written, for prefix queries.
+	words []string
+}
+
+// entry is a file of the notespace, indexed or not, for finding files by name.
+type entry struct {
+	rel   string // slash-separated, relative to the root
+	title string // frontmatter title of notes
+}
+
+// Index is an in-memory inverted index of the notes below one notespace root,
+// together with the list of all its files. It is built once in the background
+// and then kept up to date file by file.
+type Index struct {
+	root  string
+	prune []string
+
+	mu       sync.RWMutex
+	files    map[string]entry
+	docs     map[string]*document
+	postings map[string]map[string]struct{} // term -> paths
+	words    map[string]int                 // word -> number of documents
+	tokens   int                            // total length of all documents
+
+	ready  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New returns an empty index of root that skips directories named in prune.
+// Fill it by passing every file to Add, then call Finish.
+func New(root string, prune []string) *Index {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Index{
+		root:     root,
+		prune:    prune,
+		files:    map[string]entry{},
+		docs:     map[string]*document{},
+		postings: map[string]map[string]struct{}{},
+		words:    map[string]int{},
+		ready:    make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (x *Index) Root() string {
+	return x.root
+}
+
+// Add indexes the file at path, found by a walk of the notespace shared with
+// other indexes. It fails once the index is closed, so the walk can stop.
+func (x *Index) Add(path string, info fs.FileInfo) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	if !watch.Pruned(x.root, path, x.prune) {
+		x.add(path, info)
+	}
+	return nil
+}
+
+// Finish ends the build started at start by adding every file with Add.
+// Searches wait until it is called.
+func (x *Index) Finish(start time.Time) {
+	defer close(x.ready)
+
+	if x.ctx.Err() != nil {
+		return
+	}
+
+	x.mu.RLock()
+	count := len(x.docs)
+	x.mu.RUnlock()
+	log.Printf("Indexed %d notes of %s in %v", count, x.root, time.Since(start).Round(time.Millisecond))
+}
+
+// Close stops a running build. The index must not be used afterwards.
+func (x *Index) Close() {
+	x.cancel()
+}
+
+// Update reindexes path after it was created, written or removed. Directories
+// are indexed recursively; unchanged files are skipped.
+func (x *Index) Update(path string) {
+	if watch.Pruned(x.root, path, x.prune) {
+		return
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		x.Remove(path)
+		return
+	}
+	if info.IsDir() {
+		x.addTree(path)
+		return
+	}
+	x.add(path, info)
+}
+
+// Remove drops path and everything below it from the index.
+func (x *Index) Remove(path string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	prefix := path + string(filepath.Separator)
+	for filePath := range x.files {
+		if filePath == path || strings.HasPrefix(filePath, prefix) {
+			delete(x.files, filePath)
+			x.removeLocked(filePath)
+		}
+	}
+}
+
+// Apply updates the index from a batch of watcher events.
+func (x *Index) Apply(events []watch.Event) {
+	for _, event := range events {
+		if x.ctx.Err() != nil {
+			return
+		}
+		if event.Op == watch.Remove {
+			x.Remove(event.Path)
+		} else {
+			x.Update(event.Path)
+		}
+	}
+}
+
+func (x *Index) addTree(dir string) {
+	err := watch.Walk(dir, x.prune, func(path string, info fs.FileInfo) error {
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+		x.add(path, info)
+		return nil
+	})
+	if err != nil && x.ctx.Err() == nil {
+		log.Printf("Failed to index %s: %v", dir, err)
+	}
+}
+
+// add lists the file at path and indexes its content unless it is unchanged
+// since it was last indexed.
+func (x *Index) add(path string, info fs.FileInfo) {
+	if !info.Mode().IsRegular() {
+		x.Remove(path)
+		return
+	}
+	if !indexable(path, info) {
+		x.list(path, "")
+		return
+	}
+
+	x.mu.RLock()
+	doc, ok := x.docs[path]
+	x.mu.RUnlock()
+	if ok && doc.size == info.Size() && !info.ModTime().After(doc.modified) {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		x.Remove(path)
+		return
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		x.list(path, "")
+		return
+	}
+
+	doc = &document{
+		modified: info.ModTime(),
+		size:     info.Size(),
+		terms:    map[string][]int{},
+	}
+	seen := map[string]bool{}
+	for _, t := range tokenize(string(data)) {
+		doc.terms[t.term] = append(doc.terms[t.term], t.position)
+		if !seen[t.word] {
+			seen[t.word] = true
+			doc.words = append(doc.words, t.word)
+		}
+		doc.length++
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if current, ok := x.docs[path]; ok {
+		// A newer version was indexed while this one was being read
+		if current.modified.After(doc.modified) {
+			return
+		}
+		x.removeLocked(path)
+	}
+	x.files[path] = x.entry(path, file.FrontmatterTitle(bytes.NewReader(data)))
+	x.docs[path] = doc
+	x.tokens += doc.length
+	for term := range doc.terms {
+		paths, ok := x.postings[term]
+		if !ok {
+			paths = map[string]struct{}{}
+			x.postings[term] = paths
+		}
+		paths[path] = struct{}{}
+	}
+	for _, word := range doc.words {
+		x.words[word]++
+	}
+}
+
+// list records path as a file whose content is not indexed.
+func (x *Index) list(path string, title string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.removeLocked(path)
+	x.files[path] = x.entry(path, title)
+}
+
+func (x *Index) entry(path string, title string) entry {
+	rel, err := filepath.Rel(x.root, path)
+	if err != nil {
+		rel = path
+	}
+	return entry{rel: filepath.ToSlash(rel), title: title}
+}
+
+// removeLocked drops the indexed content of one file. The caller must hold x.mu.
+func (x *Index) removeLocked(path string) {
+	doc, ok := x.docs[path]
+	if !ok {
+		return
+	}
+	delete(x.docs, path)
+	x.tokens -= doc.length
+	for term := range doc.terms {
+		delete(x.postings[term], path)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	for _, word := range doc.words {
+		if x.words[word]--; x.words[word] <= 0 {
+			delete(x.words, word)
+		}
+	}
+}
+
+func indexable(path string, info fs.FileInfo) bool {
+	if info.Size() > MAX_FILE_SIZE {
+		return false
+	}
+	return slices.Contains(EXTENSIONS, strings.ToLower(filepath.Ext(path)))
+}
@@ -0,0 +1,363 @@
+package search
+
+import (
+	"context"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// BM25 parameters: k1 limits how much repeated terms count, b how much long
+// documents are penalised.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// MAX_SNIPPETS is the number of matching lines shown per result.
+const MAX_SNIPPETS = 3
+
+// SNIPPET_LENGTH is the longest snippet in bytes; longer lines are cut around the first match.
+const SNIPPET_LENGTH = 160
+
+type Result struct {
+	Path     string    `json:"path"`
+	Score    float64   `json:"score"`
+	Snippets []Snippet `json:"snippets"`
+}
+
+// Snippet is a line of a result split into the parts that matched and those that did not.
+type Snippet struct {
+	Line  int    `json:"line"`
+	Parts []Part `json:"parts"`
+}
+
+type Part struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+type clauseKind int
+
+const (
+	termClause clauseKind = iota
+	phraseClause
+	prefixClause
+)
+
+// clause is one part of a query: a word, a "quoted phrase" or a prefix*.
+// Clauses starting with - exclude the documents they match.
+type clause struct {
+	kind    clauseKind
+	terms   []string
+	prefix  string
+	exclude bool
+}
+
+// parseQuery splits query into clauses. Words joined by punctuation, such as
+// "git-based", are searched as a phrase.
+func parseQuery(query string) []clause {
+	clauses := []clause{}
+	for len(query) > 0 {
+		query = strings.TrimLeft(query, " \t\r\n")
+		if query == "" {
+			break
+		}
+
+		exclude := false
+		if query[0] == '-' && len(query) > 1 {
+			exclude = true
+			query = query[1:]
+		}
+
+		var text string
+		quoted := false
+		if query[0] == '"' {
+			end := strings.IndexByte(query[1:], '"')
+			if end < 0 {
+				text, query = query[1:], ""
+			} else {
+				text, query = query[1:end+1], query[end+2:]
+			}
+			quoted = true
+		} else {
+			end := strings.IndexAny(query, " \t\r\n")
+			if end < 0 {
+				end = len(query)
+			}
+			text, query = query[:end], query[end:]
+		}
+
+		prefix := !quoted && strings.HasSuffix(text, "*")
+		tokens := tokenize(strings.TrimRight(text, "*"))
+		c := clause{exclude: exclude}
+		switch {
+		case len(tokens) == 0:
+			continue
+		case prefix && len(tokens) == 1:
+			c.kind = prefixClause
+			c.prefix = tokens[0].word
+		case len(tokens) == 1:
+			c.kind = termClause
+			c.terms = []string{tokens[0].term}
+		default:
+			c.kind = phraseClause
+			for _, t := range tokens {
+				c.terms = append(c.terms, t.term)
+			}
+		}
+		clauses = append(clauses, c)
+	}
+	return clauses
+}
+
+// Search returns the notes matching every clause of query, best first. It
+// waits for the initial build. limit 0 returns every match.
+func (x *Index) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	select {
+	case <-x.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	clauses := parseQuery(query)
+	scores, highlight := x.rank(clauses)
+
+	results := make([]Result, 0, len(scores))
+	for path, score := range scores {
+		results = append(results, Result{Path: path, Score: score})
+	}
+	slices.SortFunc(results, func(a, b Result) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	for i := range results {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		results[i].Snippets = snippets(results[i].Path, highlight)
+	}
+	return results, nil
+}
+
+// rank scores the documents matching clauses with BM25 and returns the terms
+// to highlight in them.
+func (x *Index) rank(clauses []clause) (map[string]float64, func(token) bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var matched map[string]bool
+	excluded := map[string]bool{}
+	scored := map[string]bool{}
+	prefixes := []string{}
+
+	for _, c := range clauses {
+		docs := map[string]bool{}
+		switch c.kind {
+		case termClause:
+			for path := range x.postings[c.terms[0]] {
+				docs[path] = true
+			}
+		case prefixClause:
+			for word := range x.words {
+				if !strings.HasPrefix(word, c.prefix) {
+					continue
+				}
+				term := Stem(word)
+				for path := range x.postings[term] {
+					docs[path] = true
+				}
+				if !c.exclude {
+					scored[term] = true
+				}
+			}
+		case phraseClause:
+			for path := range x.postings[c.terms[0]] {
+				if x.hasPhrase(x.docs[path], c.terms) {
+					docs[path] = true
+				}
+			}
+		}
+
+		if c.exclude {
+			for path := range docs {
+				excluded[path] = true
+			}
+			continue
+		}
+		for _, term := range c.terms {
+			scored[term] = true
+		}
+		if c.kind == prefixClause {
+			prefixes = append(prefixes, c.prefix)
+		}
+		if matched == nil {
+			matched = docs
+			continue
+		}
+		for path := range matched {
+			if !docs[path] {
+				delete(matched, path)
+			}
+		}
+	}
+
+	scores := map[string]float64{}
+	if len(x.docs) == 0 {
+		return scores, nil
+	}
+	average := float64(x.tokens) / float64(len(x.docs))
+	total := float64(len(x.docs))
+	for path := range matched {
+		if excluded[path] {
+			continue
+		}
+		doc := x.docs[path]
+		score := 0.0
+		for term := range scored {
+			tf := float64(len(doc.terms[term]))
+			if tf == 0 {
+				continue
+			}
+			n := float64(len(x.postings[term]))
+			idf := math.Log(1 + (total-n+0.5)/(n+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(doc.length)/average))
+		}
+		scores[path] = score
+	}
+
+	highlight := func(t token) bool {
+		if scored[t.term] {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(t.word, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return scores, highlight
+}
+
+// hasPhrase reports whether terms appear consecutively in doc.
+func (x *Index) hasPhrase(doc *document, terms []string) bool {
+	if doc == nil {
+		return false
+	}
+	for _, start := range doc.terms[terms[0]] {
+		found := true
+		for i, term := range terms[1:] {
+			if _, ok := slices.BinarySearch(doc.terms[term], start+i+1); !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// snippets returns the first lines of path with highlighted words.
+func snippets(path string, highlight func(token) bool) []Snippet {
+	data, err := os.ReadFile(path)
+	if err != nil || highlight == nil {
+		return []Snippet{}
+	}
+	content := string(data)
+
+	result := []Snippet{}
+	line, lineStart := 1, 0
+	var matches []token
+	emit := func(lineEnd int) {
+		if len(matches) > 0 && len(result) < MAX_SNIPPETS {
+			result = append(result, snippet(content[lineStart:lineEnd], line, lineStart, matches))
+		}
+		matches = nil
+	}
+
+	tokens := tokenize(content)
+	for _, t := range tokens {
+		for {
+			end := strings.IndexByte(content[lineStart:], '\n')
+			if end < 0 || lineStart+end > t.start {
+				break
+			}
+			emit(lineStart + end)
+			line++
+			lineStart += end + 1
+		}
+		if len(result) >= MAX_SNIPPETS {
+			break
+		}
+		if highlight(t) {
+			matches = append(matches, t)
+		}
+	}
+	if len(result) < MAX_SNIPPETS {
+		end := strings.IndexByte(content[lineStart:], '\n')
+		if end < 0 {
+			end = len(content) - lineStart
+		}
+		emit(lineStart + end)
+	}
+	return result
+}
+
+// snippet splits text, which starts at offset in the file, around matches.
+func snippet(text string, line int, offset int, matches []token) Snippet {
+	text = strings.TrimRight(text, "\r")
+	from, to := 0, len(text)
+	if len(text) > SNIPPET_LENGTH {
+		from = max(0, matches[0].start-offset-SNIPPET_LENGTH/4)
+		to = min(len(text), from+SNIPPET_LENGTH)
+		for from > 0 && !utf8.RuneStart(text[from]) {
+			from--
+		}
+		for to < len(text) && !utf8.RuneStart(text[to]) {
+			to++
+		}
+	}
+
+	parts := []Part{}
+	if from > 0 {
+		parts = append(parts, Part{Text: "…"})
+	}
+	cursor := from
+	for _, m := range matches {
+		start, end := m.start-offset, m.end-offset
+		if start < cursor || end > to {
+			continue
+		}
+		if start > cursor {
+			parts = append(parts, Part{Text: text[cursor:start]})
+		}
+		parts = append(parts, Part{Text: text[start:end], Match: true})
+		cursor = end
+	}
+	if cursor < to {
+		parts = append(parts, Part{Text: text[cursor:to]})
+	}
+	if to < len(text) {
+		parts = append(parts, Part{Text: "…"})
+	}
+
+	// Indentation is noise in a result list
+	if len(parts) > 0 && !parts[0].Match {
+		parts[0].Text = strings.TrimLeft(parts[0].Text, " \t")
+	}
+	return Snippet{Line: line, Parts: parts}
+}
@@ -0,0 +1,239 @@
+package search
+
+import "strings"
+
+// Stem reduces an English word to its stem with the Porter algorithm, so that
+// "notes", "noted" and "noting" are found together. word must be lower case;
+// words that are not plain ASCII letters are returned unchanged.
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &stemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+type stemmer struct {
+	b []byte
+	// j marks the end of the stem while a suffix is being tested.
+	j int
+}
+
+// cons reports whether b[i] is a consonant.
+func (s *stemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.cons(i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in b[:j+1].
+func (s *stemmer) measure() int {
+	n, i := 0, 0
+	for ; i <= s.j && s.cons(i); i++ {
+	}
+	for i <= s.j {
+		for ; i <= s.j && !s.cons(i); i++ {
+		}
+		if i > s.j {
+			break
+		}
+		n++
+		for ; i <= s.j && s.cons(i); i++ {
+		}
+	}
+	return n
+}
+
+// vowelInStem reports whether b[:j+1] contains a vowel.
+func (s *stemmer) vowelInStem() bool {
+	for i := 0; i <= s.j; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleCons reports whether b[i-1:i+1] is a double consonant.
+func (s *stemmer) doubleCons(i int) bool {
+	return i >= 1 && s.b[i] == s.b[i-1] && s.cons(i)
+}
+
+// cvc reports whether b[i-2:i+1] is consonant-vowel-consonant with the last
+// consonant not w, x or y, as in "hop" but not "snow".
+func (s *stemmer) cvc(i int) bool {
+	if i < 2 || !s.cons(i) || s.cons(i-1) || !s.cons(i-2) {
+		return false
+	}
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// ends reports whether b ends with suffix and sets j to the end of the stem before it.
+func (s *stemmer) ends(suffix string) bool {
+	if !strings.HasSuffix(string(s.b), suffix) {
+		return false
+	}
+	s.j = len(s.b) - len(suffix) - 1
+	return true
+}
+
+// setTo replaces the suffix after j with replacement.
+func (s *stemmer) setTo(replacement string) {
+	s.b = append(s.b[:s.j+1], replacement...)
+}
+
+// replace replaces the suffix after j if the stem has a measure above zero.
+func (s *stemmer) replace(replacement string) {
+	if s.measure() > 0 {
+		s.setTo(replacement)
+	}
+}
+
+// step1a removes plurals: caresses -> caress, ponies -> poni, cats -> cat.
+func (s *stemmer) step1a() {
+	switch {
+	case s.ends("sses"):
+		s.b = s.b[:len(s.b)-2]
+	case s.ends("ies"):
+		s.b = s.b[:len(s.b)-2]
+	case s.ends("ss"):
+	case s.ends("s"):
+		s.b = s.b[:len(s.b)-1]
+	}
+}
+
+// step1b removes -ed and -ing: agreed -> agree, hopping -> hop, filing -> file.
+func (s *stemmer) step1b() {
+	if s.ends("eed") {
+		if s.measure() > 0 {
+			s.b = s.b[:len(s.b)-1]
+		}
+		return
+	}
+	if !(s.ends("ed") || s.ends("ing")) || !s.vowelInStem() {
+		return
+	}
+
+	s.b = s.b[:s.j+1]
+	k := len(s.b) - 1
+	switch {
+	case s.ends("at"):
+		s.setTo("ate")
+	case s.ends("bl"):
+		s.setTo("ble")
+	case s.ends("iz"):
+		s.setTo("ize")
+	case s.doubleCons(k):
+		switch s.b[k] {
+		case 'l', 's', 'z':
+		default:
+			s.b = s.b[:k]
+		}
+	default:
+		s.j = k
+		if s.measure() == 1 && s.cvc(k) {
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c turns a final y into i when there is another vowel: happy -> happi.
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+var step2Suffixes = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+}
+
+// step2 maps double suffixes to single ones: relational -> relate.
+func (s *stemmer) step2() {
+	for _, suffix := range step2Suffixes {
+		if s.ends(suffix[0]) {
+			s.replace(suffix[1])
+			return
+		}
+	}
+}
+
+var step3Suffixes = [][2]string{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step3 handles -ic-, -full, -ness etc.: hopeful -> hope.
+func (s *stemmer) step3() {
+	for _, suffix := range step3Suffixes {
+		if s.ends(suffix[0]) {
+			s.replace(suffix[1])
+			return
+		}
+	}
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 removes the remaining suffixes from long stems: adjustment -> adjust.
+func (s *stemmer) step4() {
+	for _, suffix := range step4Suffixes {
+		if !s.ends(suffix) {
+			continue
+		}
+		// -ion only goes after s or t: adoption -> adopt, but not onion
+		if suffix == "ion" && (s.j < 0 || (s.b[s.j] != 's' && s.b[s.j] != 't')) {
+			return
+		}
+		if s.measure() > 1 {
+			s.b = s.b[:s.j+1]
+		}
+		return
+	}
+}
+
+// step5 removes a final -e and reduces a final -ll: probate -> probat, controll -> control.
+func (s *stemmer) step5() {
+	s.j = len(s.b) - 1
+	if s.b[s.j] == 'e' {
+		s.j--
+		m := s.measure()
+		if m > 1 || (m == 1 && !s.cvc(s.j)) {
+			s.b = s.b[:len(s.b)-1]
+		}
+	}
+
+	s.j = len(s.b) - 1
+	if s.b[s.j] == 'l' && s.doubleCons(s.j) && s.measure() > 1 {
+		s.b = s.b[:len(s.b)-1]
+	}
+}
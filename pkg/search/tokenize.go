@@ -0,0 +1,61 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a word of a document. start and end are byte offsets into the text.
+type token struct {
+	word     string // lower case, as written
+	term     string // stemmed
+	position int
+	start    int
+	end      int
+}
+
+// tokenize splits text into words of letters and digits. Apostrophes inside a
+// word are dropped so that "don't" is one word.
+func tokenize(text string) []token {
+	tokens := []token{}
+	var word strings.Builder
+	start := -1
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		w := word.String()
+		tokens = append(tokens, token{
+			word:     w,
+			term:     Stem(w),
+			position: len(tokens),
+			start:    start,
+			end:      end,
+		})
+		word.Reset()
+		start = -1
+	}
+
+	for i, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			word.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’') && start >= 0 && i+utf8.RuneLen(r) < len(text) && isWordRune(text[i+utf8.RuneLen(r):]):
+			// Inside a word; skipped
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return tokens
+}
+
+func isWordRune(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,258 @@
+package settings
+
+import (
+	"encoding/json"
+	"log"
+	"noted/pkg/file"
+	"noted/pkg/status"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// SETTINGS_PATH is relative to the user's XDG config directory.
+const SETTINGS_PATH = "noted/settings.json"
+
+const SETTINGS_VERSION = 1
+
+type Recent struct {
+	Path     string    `json:"path"`
+	Pinned   bool      `json:"pinned"`
+	OpenedAt time.Time `json:"openedAt"`
+}
+
+// Preferences are app-wide and apply to every notespace.
+type Preferences struct {
+	// MaxRecents limits the unpinned recents; pinned entries are always kept.
+	MaxRecents int `json:"maxRecents"`
+	// SortMode is the default file tree order (see file.SortMode).
+	SortMode file.SortMode `json:"sortMode"`
+	// StartFresh opens the directory picker on launch instead of the previous session.
+	StartFresh bool `json:"startFresh"`
+}
+
+type Settings struct {
+	Version     int         `json:"version"`
+	Preferences Preferences `json:"preferences"`
+	Recents     []Recent    `json:"recents"`
+	// Session lists the editor windows that were open when the app last quit.
+	Session []SessionWindow `json:"session"`
+	// Picker is the last position of the directory picker window.
+	Picker Bounds `json:"picker"`
+}
+
+// Store keeps Settings in memory and writes every change through to disk.
+type Store struct {
+	mu       sync.RWMutex
+	path     string
+	settings Settings
+
+	// SortModeChanged, if set, is called with the SortMode of updated
+	// preferences so the file tree uses it without a restart.
+	SortModeChanged func(mode file.SortMode) error
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		Version: SETTINGS_VERSION,
+		Preferences: Preferences{
+			MaxRecents: 20,
+			SortMode:   file.SortNatural,
+		},
+		Recents: []Recent{},
+		Session: []SessionWindow{},
+	}
+}
+
+// New loads the settings from the XDG config directory, falling back to defaults.
+func New() *Store {
+	path, err := xdg.ConfigFile(SETTINGS_PATH)
+	if err != nil {
+		log.Printf("Failed to resolve settings path, settings will not persist: %v", err)
+	}
+
+	store := &Store{
+		path:     path,
+		settings: defaultSettings(),
+	}
+	if path == "" {
+		return store
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Failed to read settings: %v", err)
+		}
+		return store
+	}
+	if err := json.Unmarshal(data, &store.settings); err != nil {
+		log.Printf("Ignoring invalid settings file %s: %v", path, err)
+		store.settings = defaultSettings()
+	}
+	if store.settings.Recents == nil {
+		store.settings.Recents = []Recent{}
+	}
+	if store.settings.Session == nil {
+		store.settings.Session = []SessionWindow{}
+	}
+
+	return store
+}
+
+func (s *Store) GetSettings() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings := s.settings
+	settings.Recents = slices.Clone(s.settings.Recents)
+	settings.Session = slices.Clone(s.settings.Session)
+	return settings
+}
+
+func (s *Store) UpdatePreferences(preferences Preferences) (Settings, error) {
+	if preferences.MaxRecents <= 0 {
+		preferences.MaxRecents = defaultSettings().Preferences.MaxRecents
+	}
+	if preferences.SortMode == "" {
+		preferences.SortMode = defaultSettings().Preferences.SortMode
+	}
+	if err := file.CheckSortMode(preferences.SortMode); err != nil {
+		return s.GetSettings(), err
+	}
+
+	err := s.update(func(settings *Settings) {
+		settings.Preferences = preferences
+		trimRecents(settings)
+	})
+	if s.SortModeChanged != nil {
+		if serr := s.SortModeChanged(preferences.SortMode); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return s.GetSettings(), err
+}
+
+// GetRecents returns pinned notespaces first, then the most recently opened.
+func (s *Store) GetRecents() []Recent {
+	return s.GetSettings().Recents
+}
+
+// AddRecent marks path as just opened.
+func (s *Store) AddRecent(path string) ([]Recent, error) {
+	path = filepath.Clean(path)
+	err := s.update(func(settings *Settings) {
+		recent := Recent{Path: path}
+		if i := indexOf(settings.Recents, path); i >= 0 {
+			recent = settings.Recents[i]
+			settings.Recents = slices.Delete(settings.Recents, i, i+1)
+		}
+		recent.OpenedAt = time.Now()
+		settings.Recents = append(settings.Recents, recent)
+		trimRecents(settings)
+	})
+	return s.GetRecents(), err
+}
+
+// ImportRecents adds paths that are not known yet, oldest last. It is used to
+// move the list the frontend used to keep in localStorage.
+func (s *Store) ImportRecents(paths []string) ([]Recent, error) {
+	err := s.update(func(settings *Settings) {
+		for _, path := range paths {
+			path = filepath.Clean(path)
+			if indexOf(settings.Recents, path) < 0 {
+				settings.Recents = append(settings.Recents, Recent{Path: path})
+			}
+		}
+		trimRecents(settings)
+	})
+	return s.GetRecents(), err
+}
+
+func (s *Store) PinRecent(path string, pinned bool) ([]Recent, error) {
+	err := s.update(func(settings *Settings) {
+		if i := indexOf(settings.Recents, filepath.Clean(path)); i >= 0 {
+			settings.Recents[i].Pinned = pinned
+		}
+		trimRecents(settings)
+	})
+	return s.GetRecents(), err
+}
+
+func (s *Store) RemoveRecent(path string) ([]Recent, error) {
+	err := s.update(func(settings *Settings) {
+		if i := indexOf(settings.Recents, filepath.Clean(path)); i >= 0 {
+			settings.Recents = slices.Delete(settings.Recents, i, i+1)
+		}
+	})
+	return s.GetRecents(), err
+}
+
+// ForgetMissing removes recents whose folder no longer exists. Pinned entries are kept.
+func (s *Store) ForgetMissing() ([]Recent, error) {
+	err := s.update(func(settings *Settings) {
+		settings.Recents = slices.DeleteFunc(settings.Recents, func(recent Recent) bool {
+			info, err := os.Stat(recent.Path)
+			return !recent.Pinned && (err != nil || !info.IsDir())
+		})
+	})
+	return s.GetRecents(), err
+}
+
+// update applies modify under the lock and persists the result.
+func (s *Store) update(modify func(settings *Settings)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	modify(&s.settings)
+	s.settings.Version = SETTINGS_VERSION
+
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.settings, "", "  ")
+	if err != nil {
+		return status.New(status.FAILED_SETTINGS_WRITE, s.path, err)
+	}
+	if err := file.WriteAtomic(s.path, append(data, '\n')); err != nil {
+		log.Printf("Failed to write settings: %v", err)
+		return status.FromOS(err, s.path, status.FAILED_SETTINGS_WRITE)
+	}
+	return nil
+}
+
+// trimRecents sorts recents (pinned, then newest) and drops unpinned ones over the limit.
+func trimRecents(settings *Settings) {
+	slices.SortStableFunc(settings.Recents, func(a, b Recent) int {
+		if a.Pinned != b.Pinned {
+			if a.Pinned {
+				return -1
+			}
+			return 1
+		}
+		return b.OpenedAt.Compare(a.OpenedAt)
+	})
+
+	limit := settings.Preferences.MaxRecents
+	if limit <= 0 {
+		limit = defaultSettings().Preferences.MaxRecents
+	}
+	unpinned := 0
+	settings.Recents = slices.DeleteFunc(settings.Recents, func(recent Recent) bool {
+		if recent.Pinned {
+			return false
+		}
+		unpinned++
+		return unpinned > limit
+	})
+}
+
+func indexOf(recents []Recent, path string) int {
+	return slices.IndexFunc(recents, func(recent Recent) bool {
+		return recent.Path == path
+	})
+}
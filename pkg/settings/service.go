@@ -0,0 +1,13 @@
+package settings
+
+import (
+	"noted/pkg/status"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func Service(store *Store) application.Service {
+	return application.NewServiceWithOptions(store, application.ServiceOptions{
+		MarshalError: status.MarshalError,
+	})
+}
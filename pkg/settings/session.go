@@ -0,0 +1,64 @@
+package settings
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// Bounds is the position and size of a window in screen coordinates.
+type Bounds struct {
+	X         int  `json:"x"`
+	Y         int  `json:"y"`
+	Width     int  `json:"width"`
+	Height    int  `json:"height"`
+	Maximised bool `json:"maximised"`
+	// Screen is the ID of the display the window was on.
+	Screen string `json:"screen,omitempty"`
+}
+
+// IsZero reports whether the bounds were never recorded.
+func (b Bounds) IsZero() bool {
+	return b.Width == 0 || b.Height == 0
+}
+
+// SessionWindow is an editor window that was open when the app last quit. Its
+// tabs are kept in the notespace's workspace state, not here.
+type SessionWindow struct {
+	Path   string `json:"path"`
+	Bounds Bounds `json:"bounds"`
+	// ReadOnly windows refuse every change to the notespace.
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// GetSession returns the editor windows to reopen on launch, in the order they were opened.
+func (s *Store) GetSession() []SessionWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.settings.Session)
+}
+
+// SaveSession replaces the remembered editor windows.
+func (s *Store) SaveSession(windows []SessionWindow) error {
+	return s.update(func(settings *Settings) {
+		settings.Session = make([]SessionWindow, 0, len(windows))
+		for _, window := range windows {
+			window.Path = filepath.Clean(window.Path)
+			settings.Session = append(settings.Session, window)
+		}
+	})
+}
+
+// GetPickerBounds returns where the directory picker window was last placed.
+func (s *Store) GetPickerBounds() Bounds {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.settings.Picker
+}
+
+func (s *Store) SavePickerBounds(bounds Bounds) error {
+	return s.update(func(settings *Settings) {
+		settings.Picker = bounds
+	})
+}
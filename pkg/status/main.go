@@ -0,0 +1,197 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+)
+
+// Code is a stable identifier shared with the frontend (utils/constants/status-codes).
+// The prefix before the first underscore is the level: INFO, WARN, FAILED or SUCCESS.
+type Code string
+
+const (
+	INFO_CANCELLED Code = "INFO_CANCELLED"
+
+	FAILED_DIALOG           Code = "FAILED_DIALOG"
+	FAILED_NOT_FOUND        Code = "FAILED_NOT_FOUND"
+	FAILED_NOT_DIRECTORY    Code = "FAILED_NOT_DIRECTORY"
+	FAILED_NO_NOTESPACE     Code = "FAILED_NO_NOTESPACE"
+	FAILED_PERMISSION       Code = "FAILED_PERMISSION"
+	FAILED_NOTESPACE        Code = "FAILED_NOTESPACE"
+	FAILED_WINDOW           Code = "FAILED_WINDOW"
+	FAILED_NO_SESSION       Code = "FAILED_NO_SESSION"
+	FAILED_READ_ONLY        Code = "FAILED_READ_ONLY"
+	FAILED_CONFIG_CHECK     Code = "FAILED_CONFIG_CHECK"
+	FAILED_CONFIG_INVALID   Code = "FAILED_CONFIG_INVALID"
+	FAILED_CONFIG_MIGRATE   Code = "FAILED_CONFIG_MIGRATE"
+	FAILED_CONFIG_WRITE     Code = "FAILED_CONFIG_WRITE"
+	FAILED_CONFIG_KEY       Code = "FAILED_CONFIG_KEY"
+	FAILED_DOCTOR           Code = "FAILED_DOCTOR"
+	FAILED_DOCTOR_FIX       Code = "FAILED_DOCTOR_FIX"
+	FAILED_SETTINGS_WRITE   Code = "FAILED_SETTINGS_WRITE"
+	FAILED_FILE_READ        Code = "FAILED_FILE_READ"
+	FAILED_FILE_WRITE       Code = "FAILED_FILE_WRITE"
+	FAILED_FILE_CREATE      Code = "FAILED_FILE_CREATE"
+	FAILED_DIR_CREATE       Code = "FAILED_DIR_CREATE"
+	FAILED_TREE_SCAN        Code = "FAILED_TREE_SCAN"
+	FAILED_SORT_MODE        Code = "FAILED_SORT_MODE"
+	FAILED_GREP_PATTERN     Code = "FAILED_GREP_PATTERN"
+	FAILED_REPLACE          Code = "FAILED_REPLACE"
+	FAILED_REPLACE_CHANGED  Code = "FAILED_REPLACE_CHANGED"
+	FAILED_REPLACE_UNDO     Code = "FAILED_REPLACE_UNDO"
+	FAILED_TAG_QUERY        Code = "FAILED_TAG_QUERY"
+	FAILED_TAG_RENAME       Code = "FAILED_TAG_RENAME"
+	FAILED_FRONTMATTER      Code = "FAILED_FRONTMATTER"
+	FAILED_FRONTMATTER_EDIT Code = "FAILED_FRONTMATTER_EDIT"
+	FAILED_QUERY            Code = "FAILED_QUERY"
+	FAILED_ORDER_FILE       Code = "FAILED_ORDER_FILE"
+	FAILED_UNKNOWN          Code = "FAILED_UNKNOWN"
+	WARN_NO_GIT_INIT        Code = "WARN_NO_GIT_INIT"
+	WARN_NO_CONFIG_CREATE   Code = "WARN_NO_CONFIG_CREATE"
+	WARN_NO_CONFIG_WRITE    Code = "WARN_NO_CONFIG_WRITE"
+)
+
+// messages holds the default user message for each code. Keep in sync with messages.json.
+var messages = map[Code]string{
+	INFO_CANCELLED: "Selection cancelled",
+
+	FAILED_DIALOG:           "Failed to open dialog",
+	FAILED_NOT_FOUND:        "Folder or file does not exist",
+	FAILED_NOT_DIRECTORY:    "Not a folder",
+	FAILED_NO_NOTESPACE:     "The file is not inside a notespace",
+	FAILED_PERMISSION:       "Permission denied",
+	FAILED_NOTESPACE:        "Failed to create notespace",
+	FAILED_WINDOW:           "Failed to open editor window",
+	FAILED_NO_SESSION:       "No notespace is open in this window",
+	FAILED_READ_ONLY:        "This notespace is open read-only",
+	FAILED_CONFIG_CHECK:     "Failed to check config file",
+	FAILED_CONFIG_INVALID:   "The notespace config file is invalid",
+	FAILED_CONFIG_MIGRATE:   "Failed to upgrade the notespace config file",
+	FAILED_CONFIG_WRITE:     "Failed to save notespace settings",
+	FAILED_CONFIG_KEY:       "Unknown config key",
+	FAILED_DOCTOR:           "Failed to check the notespace",
+	FAILED_DOCTOR_FIX:       "Failed to fix a notespace problem",
+	FAILED_SETTINGS_WRITE:   "Failed to save app settings",
+	FAILED_FILE_READ:        "Failed to read file",
+	FAILED_FILE_WRITE:       "Failed to save file",
+	FAILED_FILE_CREATE:      "Failed to create file",
+	FAILED_DIR_CREATE:       "Failed to create folder",
+	FAILED_TREE_SCAN:        "Failed to read notespace files",
+	FAILED_SORT_MODE:        "Unknown sort order",
+	FAILED_GREP_PATTERN:     "The search pattern is invalid",
+	FAILED_REPLACE:          "Failed to replace in files",
+	FAILED_REPLACE_CHANGED:  "A file changed since the replacement was previewed",
+	FAILED_REPLACE_UNDO:     "Failed to undo the replacement",
+	FAILED_TAG_QUERY:        "Invalid tag query",
+	FAILED_TAG_RENAME:       "Failed to rename the tag",
+	FAILED_FRONTMATTER:      "Failed to read the frontmatter",
+	FAILED_FRONTMATTER_EDIT: "Failed to edit the frontmatter",
+	FAILED_QUERY:            "Invalid query",
+	FAILED_ORDER_FILE:       "Failed to update folder order",
+	FAILED_UNKNOWN:          "An unknown error occurred",
+	WARN_NO_GIT_INIT:        "Failed to initialize git repository",
+	WARN_NO_CONFIG_CREATE:   "Failed to create config file. Opening notes repo.",
+	WARN_NO_CONFIG_WRITE:    "Failed to write config file. Opening notes repo.",
+}
+
+// Error is returned by every service method. It marshals to the `cause` of the
+// frontend's CallError, so the UI can look the code up instead of parsing text.
+type Error struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Path    string `json:"path,omitempty"`
+	// Reason is the text of Cause, kept for logs and bug reports.
+	Reason string `json:"reason,omitempty"`
+	// Errors holds the individual errors when several are reported at once.
+	Errors []*Error `json:"errors,omitempty"`
+
+	Cause error `json:"-"`
+}
+
+func New(code Code, path string, cause error) *Error {
+	err := &Error{
+		Code:    code,
+		Message: Message(code),
+		Path:    path,
+		Cause:   cause,
+	}
+	if cause != nil {
+		err.Reason = cause.Error()
+	}
+	return err
+}
+
+// FromOS maps common filesystem failures to their own codes and everything else to fallback.
+func FromOS(err error, path string, fallback Code) *Error {
+	var serr *Error
+	switch {
+	case errors.As(err, &serr):
+		return serr
+	case errors.Is(err, os.ErrNotExist):
+		return New(FAILED_NOT_FOUND, path, err)
+	case errors.Is(err, os.ErrPermission):
+		return New(FAILED_PERMISSION, path, err)
+	}
+	return New(fallback, path, err)
+}
+
+// Join reports several errors as one, keeping the first code. It returns nil for no errors.
+func Join(errs ...*Error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Message
+	}
+	return &Error{
+		Code:    errs[0].Code,
+		Message: strings.Join(messages, "\n"),
+		Path:    errs[0].Path,
+		Errors:  errs,
+		Cause:   errs[0],
+	}
+}
+
+func Message(code Code) string {
+	if message, ok := messages[code]; ok {
+		return message
+	}
+	return messages[FAILED_UNKNOWN]
+}
+
+func (e *Error) Error() string {
+	if e.Reason != "" && len(e.Errors) == 0 {
+		return e.Message + ": " + e.Reason
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// MarshalError is used as the services' MarshalError option so that errors which
+// did not come from this package still reach the frontend with a code.
+func MarshalError(err error) []byte {
+	var serr *Error
+	if !errors.As(err, &serr) {
+		serr = New(FAILED_UNKNOWN, "", err)
+	}
+	data, jerr := json.Marshal(serr)
+	if jerr != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,144 @@
+package tags
+
+import (
+	"fmt"
+	"noted/pkg/frontmatter"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// hashtagPattern matches inline #tags. Tags may be nested with slashes, as in
+// #project/noted, but must not be all digits, which would catch issue numbers.
+var hashtagPattern = regexp.MustCompile(`(?:^|[\s(\[,;])#([\p{L}\p{N}_\-/]+)`)
+
+var codeSpanPattern = regexp.MustCompile("`[^`]*`")
+
+// linkTargetPattern matches the targets of markdown links and wiki links,
+// whose #anchors are headings, not tags: [Intro](#intro), [[#Intro]].
+var linkTargetPattern = regexp.MustCompile(`\]\([^)]*\)|\[\[[^\[\]]*\]\]`)
+
+// Parse returns the tags of a note, from the tags of its YAML or TOML
+// frontmatter and from inline #hashtags outside code, without the hash, in
+// order of appearance. Tags that differ only in case are returned once.
+func Parse(content string) []string {
+	matter, _ := frontmatter.Parse(content)
+	return ParseWith(content, matter.Data)
+}
+
+// ParseWith is Parse for a note whose frontmatter was already read into data.
+func ParseWith(content string, data map[string]any) []string {
+	found := []string{}
+	seen := map[string]bool{}
+	add := func(tag string) {
+		tag = strings.Trim(strings.TrimSpace(tag), `"'#/`)
+		if !valid(tag) || seen[strings.ToLower(tag)] {
+			return
+		}
+		seen[strings.ToLower(tag)] = true
+		found = append(found, tag)
+	}
+
+	for _, tag := range frontmatterTags(data) {
+		add(tag)
+	}
+
+	lines := strings.Split(content, "\n")
+	start := 0
+	if end, ok := frontmatterEnd(lines); ok {
+		start = min(end+1, len(lines))
+	}
+
+	fence := ""
+	for _, line := range lines[start:] {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		for _, match := range hashtagPattern.FindAllStringSubmatch(blank(line), -1) {
+			add(match[1])
+		}
+	}
+	return found
+}
+
+// Match reports whether tag is want or nested below it, ignoring case: #project
+// matches #project/noted.
+func Match(tag string, want string) bool {
+	tag, want = strings.ToLower(tag), strings.ToLower(strings.TrimPrefix(want, "#"))
+	return tag == want || strings.HasPrefix(tag, want+"/")
+}
+
+// blank replaces code spans and link targets in line with spaces, so that
+// tags are looked for in the rest of the line only, at the same offsets.
+func blank(line string) string {
+	spaces := func(s string) string {
+		return strings.Repeat(" ", len(s))
+	}
+	return linkTargetPattern.ReplaceAllStringFunc(codeSpanPattern.ReplaceAllStringFunc(line, spaces), spaces)
+}
+
+// frontmatterEnd returns the index of the line closing the YAML or TOML
+// frontmatter that opens lines, or the number of lines if it is not closed.
+// ok is false when lines do not start with frontmatter.
+func frontmatterEnd(lines []string) (end int, ok bool) {
+	delimiter := strings.TrimSpace(strings.TrimPrefix(lines[0], "\uFEFF"))
+	if delimiter != frontmatter.DELIMITERS[frontmatter.FormatYAML] && delimiter != frontmatter.DELIMITERS[frontmatter.FormatTOML] {
+		return 0, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed == delimiter || (delimiter == "---" && trimmed == "...") {
+			return i, true
+		}
+	}
+	return len(lines), true
+}
+
+// frontmatterTags reads the tags key of decoded frontmatter, written as a list
+// or as one string of words.
+func frontmatterTags(data map[string]any) []string {
+	found := []string{}
+	words := func(value string) []string {
+		return strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+	for _, name := range []string{"tags", "tag"} {
+		for key, value := range data {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			switch v := value.(type) {
+			case string:
+				found = append(found, words(v)...)
+			case []any:
+				for _, item := range v {
+					if item != nil {
+						found = append(found, words(fmt.Sprint(item))...)
+					}
+				}
+			}
+		}
+	}
+	return found
+}
+
+func valid(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if !unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
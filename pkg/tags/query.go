@@ -0,0 +1,186 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"noted/pkg/status"
+	"strings"
+)
+
+// Query selects notes by their tags. A tag also matches the tags nested below
+// it. Terms next to each other must all match; OR, NOT and parentheses combine
+// them, and -tag is short for NOT tag:
+//
+//	#project/alpha AND (draft OR review) -archived
+type Query struct {
+	root expr
+}
+
+type expr interface {
+	match(tags []string) bool
+}
+
+type tagExpr string
+
+type notExpr struct{ expr }
+
+type andExpr []expr
+
+type orExpr []expr
+
+func (t tagExpr) match(tags []string) bool {
+	for _, tag := range tags {
+		if Match(tag, string(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n notExpr) match(tags []string) bool {
+	return !n.expr.match(tags)
+}
+
+func (a andExpr) match(tags []string) bool {
+	for _, e := range a {
+		if !e.match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o orExpr) match(tags []string) bool {
+	for _, e := range o {
+		if e.match(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseQuery parses a tag query. Operators may be written in any case; && || !
+// are accepted too.
+func ParseQuery(query string) (Query, error) {
+	p := &parser{tokens: tokenize(query)}
+	if len(p.tokens) == 0 {
+		return Query{}, status.New(status.FAILED_TAG_QUERY, query, errors.New("empty query"))
+	}
+	root, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	if err != nil {
+		return Query{}, status.New(status.FAILED_TAG_QUERY, query, err)
+	}
+	return Query{root: root}, nil
+}
+
+// Match reports whether a note with the given tags is selected.
+func (q Query) Match(tags []string) bool {
+	return q.root != nil && q.root.match(tags)
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) or() (expr, error) {
+	terms := orExpr{}
+	for {
+		term, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if op := strings.ToUpper(p.peek()); op != "OR" && op != "||" {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) and() (expr, error) {
+	terms := andExpr{}
+	for {
+		term, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+
+		next := strings.ToUpper(p.peek())
+		if next == "AND" || next == "&&" {
+			p.pos++
+			continue
+		}
+		if next == "" || next == ")" || next == "OR" || next == "||" {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) not() (expr, error) {
+	token := p.peek()
+	switch strings.ToUpper(token) {
+	case "":
+		return nil, errors.New("missing tag at the end")
+	case "NOT", "!", "-":
+		p.pos++
+		term, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{term}, nil
+	case "(":
+		p.pos++
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return inner, nil
+	case ")", "AND", "&&", "OR", "||":
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	p.pos++
+	tag := strings.TrimPrefix(token, "#")
+	if tag == "" {
+		return nil, errors.New("empty tag")
+	}
+	return tagExpr(tag), nil
+}
+
+// tokenize splits a query into tags, operators and parentheses. A leading - or
+// ! is split from the tag it negates.
+func tokenize(query string) []string {
+	tokens := []string{}
+	for _, field := range strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(query)) {
+		for len(field) > 1 && (field[0] == '-' || field[0] == '!') {
+			tokens = append(tokens, field[:1])
+			field = field[1:]
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
@@ -0,0 +1,157 @@
+package tags
+
+import (
+	"noted/pkg/frontmatter"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// namePattern matches a whole tag name without the hash.
+var namePattern = regexp.MustCompile(`^[\p{L}\p{N}_\-]+(?:/[\p{L}\p{N}_\-]+)*$`)
+
+// frontmatterItemPattern matches one item of a frontmatter tags value, with an
+// optional hash and without quotes.
+var frontmatterItemPattern = regexp.MustCompile(`#?([^\s,\[\]"'#]+)`)
+
+// Valid reports whether name can be written as an inline #tag.
+func Valid(name string) bool {
+	return namePattern.MatchString(name) && valid(name)
+}
+
+// Rename replaces the tag from, and the tags nested below it, with to in the
+// frontmatter tags and the inline hashtags of content. Case is ignored when
+// matching; the rest of the note is left as it was. It returns the new content
+// and the number of tags replaced.
+func Rename(content string, from string, to string) (string, int) {
+	from, to = strings.TrimPrefix(from, "#"), strings.TrimPrefix(to, "#")
+	renamed := 0
+	replace := func(tag string) string {
+		if !Match(tag, from) {
+			return tag
+		}
+		renamed++
+		nested := strings.SplitN(tag, "/", strings.Count(from, "/")+2)
+		return strings.Join(append([]string{to}, nested[strings.Count(from, "/")+1:]...), "/")
+	}
+
+	lines := strings.Split(content, "\n")
+	start := 0
+	if end, ok := frontmatterEnd(lines); ok {
+		toml := strings.TrimSpace(strings.TrimPrefix(lines[0], "\uFEFF")) == frontmatter.DELIMITERS[frontmatter.FormatTOML]
+		renameFrontmatter(lines[1:end], toml, replace)
+		start = min(end+1, len(lines))
+	}
+
+	fence := ""
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		matches := hashtagPattern.FindAllStringSubmatchIndex(blank(lines[i]), -1)
+		lines[i] = replaceGroups(lines[i], matches, replace)
+	}
+	if renamed == 0 {
+		return content, 0
+	}
+	return strings.Join(lines, "\n"), renamed
+}
+
+// renameFrontmatter rewrites the items of the tags key in the YAML or TOML
+// frontmatter lines in place, leaving comments alone.
+func renameFrontmatter(lines []string, toml bool, replace func(string) string) {
+	separator := ":"
+	if toml {
+		separator = "="
+	}
+	for i := 0; i < len(lines); i++ {
+		// Keys below a TOML [table] are not top-level
+		if toml && strings.HasPrefix(strings.TrimSpace(lines[i]), "[") {
+			return
+		}
+		key, value, ok := strings.Cut(lines[i], separator)
+		if !ok || strings.HasPrefix(key, " ") || strings.HasPrefix(key, "\t") {
+			continue
+		}
+		if name := strings.ToLower(strings.Trim(strings.TrimSpace(key), `"'`)); name != "tags" && name != "tag" {
+			continue
+		}
+
+		value = uncommented(value, toml)
+		if strings.TrimSpace(value) != "" {
+			lines[i] = renameItems(lines[i], len(key)+len(separator), toml, -1, replace)
+			// A list in brackets may go on over the next lines
+			for open := strings.Contains(value, "[") && !strings.Contains(value, "]"); open && i+1 < len(lines); {
+				i++
+				lines[i] = renameItems(lines[i], 0, toml, -1, replace)
+				open = !strings.Contains(uncommented(lines[i], toml), "]")
+			}
+			continue
+		}
+
+		for i+1 < len(lines) {
+			item := lines[i+1]
+			dash := strings.IndexFunc(item, func(r rune) bool { return !unicode.IsSpace(r) })
+			if dash < 0 || item[dash] != '-' {
+				break
+			}
+			lines[i+1] = renameItems(item, dash+1, toml, 1, replace)
+			i++
+		}
+	}
+}
+
+// renameItems replaces up to n tags in line after offset and before a comment.
+func renameItems(line string, offset int, toml bool, n int, replace func(string) string) string {
+	matches := frontmatterItemPattern.FindAllStringSubmatchIndex(uncommented(line[offset:], toml), n)
+	for _, match := range matches {
+		for j := range match {
+			match[j] += offset
+		}
+	}
+	return replaceGroups(line, matches, replace)
+}
+
+// uncommented returns value up to a comment. In YAML a comment starts with a
+// # after a space, so tags: [#a] holds a tag; in TOML any # outside a string
+// starts one.
+func uncommented(value string, toml bool) string {
+	quote := rune(0)
+	for i, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (toml || i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			return value[:i]
+		}
+	}
+	return value
+}
+
+// replaceGroups replaces the first submatch of each match in line with what
+// replace returns for it.
+func replaceGroups(line string, matches [][]int, replace func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[2], match[3]
+		b.WriteString(line[last:start])
+		b.WriteString(replace(line[start:end]))
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
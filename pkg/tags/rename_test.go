@@ -0,0 +1,128 @@
+package tags
+
+import "testing"
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		from    string
+		to      string
+		want    string
+		count   int
+	}{
+		{
+			name:    "inline and nested",
+			content: "Notes #project and #project/noted, not #projects\n",
+			from:    "project",
+			to:      "work",
+			want:    "Notes #work and #work/noted, not #projects\n",
+			count:   2,
+		},
+		{
+			name:    "case is ignored",
+			content: "(#Project)",
+			from:    "#project",
+			to:      "#work",
+			want:    "(#work)",
+			count:   1,
+		},
+		{
+			name:    "nested from",
+			content: "#project/noted/ui #project",
+			from:    "project/noted",
+			to:      "app",
+			want:    "#app/ui #project",
+			count:   1,
+		},
+		{
+			name:    "code is left alone",
+			content: "`#project` #project\n```\n#project\n```\n~~~md\n#project\n~~~\n",
+			from:    "project",
+			to:      "work",
+			want:    "`#project` #work\n```\n#project\n```\n~~~md\n#project\n~~~\n",
+			count:   1,
+		},
+		{
+			name:    "link anchors are left alone",
+			content: "[intro](#project) [[#project]] [[note#project]] #project",
+			from:    "project",
+			to:      "work",
+			want:    "[intro](#project) [[#project]] [[note#project]] #work",
+			count:   1,
+		},
+		{
+			name:    "no match",
+			content: "#other\n",
+			from:    "project",
+			to:      "work",
+			want:    "#other\n",
+			count:   0,
+		},
+		{
+			name:    "yaml flow list keeps comment",
+			content: "---\ntags: [project, other] # project\n---\n",
+			from:    "project",
+			to:      "work",
+			want:    "---\ntags: [work, other] # project\n---\n",
+			count:   1,
+		},
+		{
+			name:    "yaml flow list with hashes",
+			content: "---\ntags: [#project, \"#project/x\"]\n---\n",
+			from:    "project",
+			to:      "work",
+			want:    "---\ntags: [#work, \"#work/x\"]\n---\n",
+			count:   2,
+		},
+		{
+			name:    "yaml flow list over lines",
+			content: "---\ntags: [\n  project, # first\n  other\n]\ntitle: project\n---\n",
+			from:    "project",
+			to:      "work",
+			want:    "---\ntags: [\n  work, # first\n  other\n]\ntitle: project\n---\n",
+			count:   1,
+		},
+		{
+			name:    "yaml block list",
+			content: "---\nTags:\n  - project\n  - \"project/x\" # project\ntitle: project\n---\n",
+			from:    "project",
+			to:      "work",
+			want:    "---\nTags:\n  - work\n  - \"work/x\" # project\ntitle: project\n---\n",
+			count:   2,
+		},
+		{
+			name:    "yaml string of words",
+			content: "---\ntag: other project\n---\n",
+			from:    "project",
+			to:      "work",
+			want:    "---\ntag: other work\n---\n",
+			count:   1,
+		},
+		{
+			name:    "toml top-level tags only",
+			content: "+++\ntags = [\"project\"] # project\n[extra]\ntags = [\"project\"]\n+++\n",
+			from:    "project",
+			to:      "work",
+			want:    "+++\ntags = [\"work\"] # project\n[extra]\ntags = [\"project\"]\n+++\n",
+			count:   1,
+		},
+		{
+			name:    "crlf",
+			content: "---\r\ntags: [project]\r\n---\r\n#project\r\n",
+			from:    "project",
+			to:      "work",
+			want:    "---\r\ntags: [work]\r\n---\r\n#work\r\n",
+			count:   2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, count := Rename(test.content, test.from, test.to)
+			if got != test.want || count != test.count {
+				t.Errorf("Rename = %q, %d\nwant %q, %d", got, count, test.want, test.count)
+			}
+		})
+	}
+}
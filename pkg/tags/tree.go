@@ -0,0 +1,101 @@
+package tags
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tag is one level of the tag hierarchy: #project/alpha is the tag alpha below
+// project.
+type Tag struct {
+	// Name is the full tag without the hash, as first spelled in the notespace.
+	Name string `json:"name"`
+	// Label is the last part of the name.
+	Label string `json:"label"`
+	// Count is the number of notes with exactly this tag.
+	Count int `json:"count"`
+	// Total is the number of notes with this tag or one nested below it.
+	Total    int   `json:"total"`
+	Children []Tag `json:"children"`
+}
+
+// Note is a note selected by a tag query.
+type Note struct {
+	Path string   `json:"path"`
+	Tags []string `json:"tags"`
+}
+
+type treeNode struct {
+	name     string
+	count    int
+	notes    map[string]struct{}
+	children map[string]*treeNode
+}
+
+// Tree nests the tags of notes, given by path, by their slashes and counts the
+// notes of each. Tags that differ only in case are merged. Siblings are sorted
+// by name.
+func Tree(notes map[string][]string) []Tag {
+	paths := make([]string, 0, len(notes))
+	for path := range notes {
+		paths = append(paths, path)
+	}
+	// Sorted, so the spelling kept for a tag does not change between calls
+	slices.Sort(paths)
+
+	root := &treeNode{children: map[string]*treeNode{}}
+	for _, path := range paths {
+		for _, tag := range notes[path] {
+			node := root
+			parts := strings.Split(tag, "/")
+			for i, part := range parts {
+				key := strings.ToLower(part)
+				child, ok := node.children[key]
+				if !ok {
+					child = &treeNode{
+						name:     strings.Join(parts[:i+1], "/"),
+						notes:    map[string]struct{}{},
+						children: map[string]*treeNode{},
+					}
+					node.children[key] = child
+				}
+				child.notes[path] = struct{}{}
+				node = child
+			}
+			node.count++
+		}
+	}
+	return root.tags()
+}
+
+func (n *treeNode) tags() []Tag {
+	found := make([]Tag, 0, len(n.children))
+	for _, child := range n.children {
+		found = append(found, Tag{
+			Name:     child.name,
+			Label:    child.name[strings.LastIndex(child.name, "/")+1:],
+			Count:    child.count,
+			Total:    len(child.notes),
+			Children: child.tags(),
+		})
+	}
+	slices.SortFunc(found, func(a, b Tag) int {
+		return strings.Compare(strings.ToLower(a.Label), strings.ToLower(b.Label))
+	})
+	return found
+}
+
+// Select returns the notes, given by path, that match query, sorted by path.
+// Notes without tags are matched with an empty list.
+func Select(notes map[string][]string, query Query) []Note {
+	selected := []Note{}
+	for path, tags := range notes {
+		if query.Match(tags) {
+			selected = append(selected, Note{Path: path, Tags: slices.Clone(tags)})
+		}
+	}
+	slices.SortFunc(selected, func(a, b Note) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return selected
+}
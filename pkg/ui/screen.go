@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// ClampToScreen moves window back into visible screen space once it is ready.
+// Displays may have been disconnected or rearranged since its bounds were saved;
+// screenID is the display it was on, preferred while it is still connected.
+func ClampToScreen(window *application.WebviewWindow, screenID string) {
+	var off func()
+	off = window.OnWindowEvent(events.Common.WindowRuntimeReady, func(*application.WindowEvent) {
+		off()
+		if window.IsMaximised() {
+			return
+		}
+
+		// The screen list is only kept up to date on some platforms
+		screens := application.Get().Screen.GetAll()
+		if len(screens) == 0 {
+			if current, err := window.GetScreen(); err == nil && current != nil {
+				screens = []*application.Screen{current}
+			}
+		}
+
+		bounds := window.Bounds()
+		screen := pickScreen(bounds, screens, screenID)
+		if screen == nil || bounds.Width == 0 || bounds.Height == 0 {
+			return
+		}
+		if clamped := clampBounds(bounds, screen.WorkArea); clamped != bounds {
+			window.SetBounds(clamped)
+		}
+	})
+}
+
+// pickScreen returns the display to keep bounds on: the saved one if it is still
+// connected, otherwise the one overlapping bounds most, otherwise the primary.
+func pickScreen(bounds application.Rect, screens []*application.Screen, screenID string) *application.Screen {
+	var best, primary *application.Screen
+	bestOverlap := 0
+	for _, screen := range screens {
+		if screenID != "" && screen.ID == screenID {
+			return screen
+		}
+		if screen.IsPrimary || primary == nil {
+			primary = screen
+		}
+		if overlap := overlapArea(bounds, screen.WorkArea); overlap > bestOverlap {
+			best, bestOverlap = screen, overlap
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return primary
+}
+
+// clampBounds shrinks bounds to fit area and moves it fully inside.
+func clampBounds(bounds application.Rect, area application.Rect) application.Rect {
+	if area.Width <= 0 || area.Height <= 0 {
+		return bounds
+	}
+	bounds.Width = min(bounds.Width, area.Width)
+	bounds.Height = min(bounds.Height, area.Height)
+	bounds.X = max(area.X, min(bounds.X, area.X+area.Width-bounds.Width))
+	bounds.Y = max(area.Y, min(bounds.Y, area.Y+area.Height-bounds.Height))
+	return bounds
+}
+
+func overlapArea(a application.Rect, b application.Rect) int {
+	width := min(a.X+a.Width, b.X+b.Width) - max(a.X, b.X)
+	height := min(a.Y+a.Height, b.Y+b.Height) - max(a.Y, b.Y)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	return width * height
+}
@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"net/url"
+
 	"github.com/leaanthony/u"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
-func OpenDirectoryWindow(app *application.App) *application.WebviewWindow {
-	return app.Window.NewWithOptions(application.WebviewWindowOptions{
+// OpenDirectoryWindow opens the notespace picker at position, or centered if
+// position is nil.
+func OpenDirectoryWindow(app *application.App, position *application.Point) *application.WebviewWindow {
+	options := application.WebviewWindowOptions{
 		Title:               "Noted",
 		Name:                "Open Notes",
 		Width:               744,
@@ -29,11 +33,31 @@ func OpenDirectoryWindow(app *application.App) *application.WebviewWindow {
 			},
 		},
 		URL: "/",
-	})
+	}
+	if position != nil {
+		options.InitialPosition = application.WindowXY
+		options.X, options.Y = position.X, position.Y
+	}
+
+	return app.Window.NewWithOptions(options)
+}
+
+// EditorOptions restore an editor window from a previous session. The zero value
+// opens a default-sized window with no note selected.
+type EditorOptions struct {
+	// File is the note to show first.
+	File      string
+	Bounds    application.Rect
+	Maximised bool
 }
 
-func EditorWindow(app *application.App, path string, title string) *application.WebviewWindow {
-	return app.Window.NewWithOptions(application.WebviewWindowOptions{
+func EditorWindow(app *application.App, path string, title string, opts EditorOptions) *application.WebviewWindow {
+	query := url.Values{"root": {path}}
+	if opts.File != "" {
+		query.Set("file", opts.File)
+	}
+
+	options := application.WebviewWindowOptions{
 		Title:            title,
 		Name:             "Editor",
 		MinHeight:        480,
@@ -53,6 +77,16 @@ func EditorWindow(app *application.App, path string, title string) *application.
 				AllowsBackForwardNavigationGestures: u.False,
 			},
 		},
-		URL: "/editor?root=" + path,
-	})
+		URL: "/editor?" + query.Encode(),
+	}
+	if opts.Bounds.Width > 0 && opts.Bounds.Height > 0 {
+		options.InitialPosition = application.WindowXY
+		options.X, options.Y = opts.Bounds.X, opts.Bounds.Y
+		options.Width, options.Height = opts.Bounds.Width, opts.Bounds.Height
+	}
+	if opts.Maximised {
+		options.StartState = application.WindowStateMaximised
+	}
+
+	return app.Window.NewWithOptions(options)
 }
@@ -0,0 +1,210 @@
+package watch
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Delay is how long the watcher waits for more changes before delivering a batch.
+// Editors and git checkouts write in bursts; one batch per burst is enough.
+const Delay = 150 * time.Millisecond
+
+type Op string
+
+const (
+	Create Op = "create"
+	Write  Op = "write"
+	Remove Op = "remove"
+)
+
+type Event struct {
+	Path string `json:"path"`
+	Op   Op     `json:"op"`
+}
+
+// Watcher reports changes anywhere below a notespace root in debounced batches.
+type Watcher struct {
+	root  string
+	prune []string
+	fs    *fsnotify.Watcher
+
+	mu          sync.Mutex
+	pending     map[string]Op
+	timer       *time.Timer
+	nextID      int
+	subscribers map[int]func(events []Event)
+	done        chan struct{}
+}
+
+// New starts watching root recursively, skipping directories named in prune.
+func New(root string, prune []string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		root:        root,
+		prune:       prune,
+		fs:          fsw,
+		pending:     map[string]Op{},
+		subscribers: map[int]func(events []Event){},
+		done:        make(chan struct{}),
+	}
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	go w.loop()
+	return w, nil
+}
+
+func (w *Watcher) Root() string {
+	return w.root
+}
+
+// Subscribe registers fn for every batch of changes and returns a function that removes it.
+func (w *Watcher) Subscribe(fn func(events []Event)) func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	// Windows closing and the app shutting down may both close the watcher
+	select {
+	case <-w.done:
+		w.mu.Unlock()
+		return nil
+	default:
+		close(w.done)
+	}
+	w.mu.Unlock()
+
+	return w.fs.Close()
+}
+
+func (w *Watcher) loop() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("watch error in %s: %v", w.root, err)
+		}
+	}
+}
+
+func (w *Watcher) handle(event fsnotify.Event) {
+	var op Op
+	switch {
+	case event.Has(fsnotify.Create):
+		op = Create
+		if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+			if err := w.addTree(event.Name); err != nil {
+				log.Printf("failed to watch %s: %v", event.Name, err)
+			}
+		}
+	case event.Has(fsnotify.Write):
+		op = Write
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		op = Remove
+	default:
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// A file created and then written in the same burst is still a creation
+	if previous, ok := w.pending[event.Name]; !ok || op != Write || previous != Create {
+		w.pending[event.Name] = op
+	}
+	if w.timer == nil {
+		w.timer = time.AfterFunc(Delay, w.flush)
+	} else {
+		w.timer.Reset(Delay)
+	}
+}
+
+func (w *Watcher) flush() {
+	w.mu.Lock()
+	events := make([]Event, 0, len(w.pending))
+	for path, op := range w.pending {
+		events = append(events, Event{Path: path, Op: op})
+	}
+	w.pending = map[string]Op{}
+	subscribers := make([]func(events []Event), 0, len(w.subscribers))
+	for _, fn := range w.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	w.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+	slices.SortFunc(events, func(a, b Event) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	for _, fn := range subscribers {
+		fn(events)
+	}
+}
+
+// addTree watches dir and every directory below it that is not pruned.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped rather than failing the whole watch
+			if path == dir {
+				return err
+			}
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && slices.Contains(w.prune, d.Name()) {
+			return filepath.SkipDir
+		}
+		if err := w.fs.Add(path); err != nil {
+			if path == dir {
+				return err
+			}
+			// One directory that cannot be watched, e.g. past the watch limit,
+			// should not stop changes elsewhere from being reported
+			log.Printf("failed to watch %s: %v", path, err)
+		}
+		return nil
+	})
+}
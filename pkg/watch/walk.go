@@ -0,0 +1,47 @@
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Walk calls fn for every regular file below dir, skipping directories named in
+// prune. Entries that cannot be read are skipped; an error from fn stops the walk.
+func Walk(dir string, prune []string, fn func(path string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if path != dir && slices.Contains(prune, entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		return fn(path, info)
+	})
+}
+
+// Pruned reports whether path is, or is inside, a directory below root named in
+// prune. Walk never reaches such paths; watchers and save hooks still report them.
+func Pruned(root string, path string, prune []string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if slices.Contains(prune, part) {
+			return true
+		}
+	}
+	return false
+}
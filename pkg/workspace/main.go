@@ -0,0 +1,315 @@
+package workspace
+
+import (
+	"encoding/json"
+	"log"
+	"maps"
+	"noted/pkg/file"
+	"noted/pkg/settings"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// STATE_PATH is relative to the notespace root. The file is local to the
+// machine, so it is git-ignored through GITIGNORE_PATH.
+const STATE_PATH = "/.noted/state.json"
+const GITIGNORE_PATH = "/.noted/.gitignore"
+
+const STATE_VERSION = 1
+
+// RECENT_LIMIT is how many recently opened notes are remembered.
+const RECENT_LIMIT = 50
+
+// Delay is how long updates are collected before the state file is written.
+// Cursor positions change on every keystroke; one write per pause is enough.
+const Delay = 500 * time.Millisecond
+
+// Cursor is a text selection in a note.
+type Cursor struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// State is what a notespace remembers between launches. Paths are absolute in
+// the API and stored relative to the notespace root, so the state moves with the folder.
+type State struct {
+	Version   int               `json:"version"`
+	Tabs      []string          `json:"tabs"`
+	ActiveTab string            `json:"activeTab,omitempty"`
+	Bookmarks []string          `json:"bookmarks"`
+	Cursors   map[string]Cursor `json:"cursors"`
+	// Recent lists the notes last made active, most recent first.
+	Recent []string `json:"recent"`
+	// Window is where the notespace window was last placed on this machine.
+	Window *settings.Bounds `json:"window,omitempty"`
+}
+
+// Patch holds the fields to change. Nil fields are left as they are; cursors
+// are merged into the existing ones and a nil cursor removes the entry.
+type Patch struct {
+	Tabs      *[]string          `json:"tabs,omitempty"`
+	ActiveTab *string            `json:"activeTab,omitempty"`
+	Bookmarks *[]string          `json:"bookmarks,omitempty"`
+	Cursors   map[string]*Cursor `json:"cursors,omitempty"`
+	Window    *settings.Bounds   `json:"window,omitempty"`
+}
+
+// Store keeps the state of one notespace in memory and writes it back after Delay.
+type Store struct {
+	root string
+	// readOnly stores keep changes in memory and never write the state file.
+	readOnly bool
+
+	mu    sync.Mutex
+	state State
+	timer *time.Timer
+	dirty bool
+}
+
+func emptyState() State {
+	return State{
+		Version:   STATE_VERSION,
+		Tabs:      []string{},
+		Bookmarks: []string{},
+		Cursors:   map[string]Cursor{},
+		Recent:    []string{},
+	}
+}
+
+// Open loads the state of the notespace at root. A missing or unreadable file
+// starts an empty state; the file is only written once something changes.
+func Open(root string) *Store {
+	s := &Store{
+		root:  root,
+		state: emptyState(),
+	}
+
+	path := filepath.Join(root, STATE_PATH)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Failed to read workspace state: %v", err)
+		}
+		return s
+	}
+
+	state := emptyState()
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.Printf("Ignoring invalid workspace state %s: %v", path, err)
+		return s
+	}
+
+	// Notes deleted or moved outside the app would otherwise linger forever
+	exists := func(rel string) bool {
+		_, err := os.Stat(s.abs(rel))
+		return err == nil
+	}
+	s.state.Tabs = slices.DeleteFunc(state.Tabs, func(rel string) bool { return !exists(rel) })
+	s.state.Bookmarks = slices.DeleteFunc(state.Bookmarks, func(rel string) bool { return !exists(rel) })
+	s.state.Recent = slices.DeleteFunc(state.Recent, func(rel string) bool { return !exists(rel) })
+	if state.ActiveTab != "" && slices.Contains(s.state.Tabs, state.ActiveTab) {
+		s.state.ActiveTab = state.ActiveTab
+	}
+	for rel, cursor := range state.Cursors {
+		if exists(rel) {
+			s.state.Cursors[rel] = cursor
+		}
+	}
+	s.state.Window = state.Window
+	if s.state.Tabs == nil {
+		s.state.Tabs = []string{}
+	}
+	if s.state.Bookmarks == nil {
+		s.state.Bookmarks = []string{}
+	}
+	if s.state.Recent == nil {
+		s.state.Recent = []string{}
+	}
+
+	return s
+}
+
+// OpenReadOnly loads the state of the notespace at root like Open, but the
+// store never writes it back.
+func OpenReadOnly(root string) *Store {
+	s := Open(root)
+	s.readOnly = true
+	return s
+}
+
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Get returns the state with absolute paths.
+func (s *Store) Get() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.convert(s.state, s.abs)
+}
+
+// Update applies patch and schedules a write.
+func (s *Store) Update(patch Patch) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if patch.Tabs != nil {
+		s.state.Tabs = s.relAll(*patch.Tabs)
+	}
+	if patch.ActiveTab != nil {
+		s.state.ActiveTab = s.rel(*patch.ActiveTab)
+		if s.state.ActiveTab != "" {
+			s.state.Recent = slices.DeleteFunc(s.state.Recent, func(rel string) bool { return rel == s.state.ActiveTab })
+			s.state.Recent = slices.Insert(s.state.Recent, 0, s.state.ActiveTab)
+			s.state.Recent = s.state.Recent[:min(len(s.state.Recent), RECENT_LIMIT)]
+		}
+	}
+	if patch.Bookmarks != nil {
+		s.state.Bookmarks = s.relAll(*patch.Bookmarks)
+	}
+	for path, cursor := range patch.Cursors {
+		if cursor == nil {
+			delete(s.state.Cursors, s.rel(path))
+		} else {
+			s.state.Cursors[s.rel(path)] = *cursor
+		}
+	}
+	if patch.Window != nil {
+		window := *patch.Window
+		s.state.Window = &window
+	}
+
+	if s.readOnly {
+		return s.convert(s.state, s.abs)
+	}
+	s.dirty = true
+	if s.timer == nil {
+		s.timer = time.AfterFunc(Delay, func() {
+			if err := s.Flush(); err != nil {
+				log.Printf("Failed to write workspace state of %s: %v", s.root, err)
+			}
+		})
+	} else {
+		s.timer.Reset(Delay)
+	}
+
+	return s.convert(s.state, s.abs)
+}
+
+// Flush writes pending changes immediately.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !s.dirty {
+		return nil
+	}
+
+	if err := Ignore(s.root, filepath.Base(STATE_PATH)); err != nil {
+		log.Printf("Failed to git-ignore workspace state of %s: %v", s.root, err)
+	}
+
+	s.state.Version = STATE_VERSION
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := file.WriteAtomic(filepath.Join(s.root, STATE_PATH), append(data, '\n')); err != nil {
+		return err
+	}
+
+	s.dirty = false
+	return nil
+}
+
+// Ignore adds name, a file in the .noted folder, to .noted/.gitignore unless it
+// is listed already.
+func Ignore(root string, name string) error {
+	path := filepath.Join(root, GITIGNORE_PATH)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == name || line == "/"+name {
+			return nil
+		}
+	}
+
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	data = append(data, name+"\n"...)
+	return file.WriteAtomic(path, data)
+}
+
+func (s *Store) convert(state State, path func(string) string) State {
+	converted := State{
+		Version:   state.Version,
+		Tabs:      make([]string, len(state.Tabs)),
+		Bookmarks: make([]string, len(state.Bookmarks)),
+		Cursors:   make(map[string]Cursor, len(state.Cursors)),
+		Recent:    make([]string, len(state.Recent)),
+	}
+	for i, tab := range state.Tabs {
+		converted.Tabs[i] = path(tab)
+	}
+	for i, bookmark := range state.Bookmarks {
+		converted.Bookmarks[i] = path(bookmark)
+	}
+	for i, recent := range state.Recent {
+		converted.Recent[i] = path(recent)
+	}
+	if state.ActiveTab != "" {
+		converted.ActiveTab = path(state.ActiveTab)
+	}
+	for _, key := range slices.Sorted(maps.Keys(state.Cursors)) {
+		converted.Cursors[path(key)] = state.Cursors[key]
+	}
+	if state.Window != nil {
+		window := *state.Window
+		converted.Window = &window
+	}
+	return converted
+}
+
+// rel makes path relative to the root with forward slashes. Paths outside the
+// root are kept absolute.
+func (s *Store) rel(path string) string {
+	if path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (s *Store) relAll(paths []string) []string {
+	rels := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if rel := s.rel(path); rel != "" && !slices.Contains(rels, rel) {
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+func (s *Store) abs(rel string) string {
+	if filepath.IsAbs(rel) {
+		return rel
+	}
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}